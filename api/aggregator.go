@@ -0,0 +1,256 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type (
+	// VendorPage is a public status page watched by an Aggregator.
+	VendorPage struct {
+		// Name labels the page in snapshots, the page name reported by the
+		// API is used when empty.
+		Name string
+		// URL is where the page is served, e.g. "https://www.githubstatus.com".
+		URL string
+	}
+
+	// AggregatorOptions tunes an Aggregator. Zero fields take the defaults
+	// documented on each field.
+	AggregatorOptions struct {
+		// Interval is the delay between two polls of every page made by Run,
+		// one minute by default.
+		Interval time.Duration
+		// Concurrency caps the pages polled at once, 8 by default.
+		Concurrency int
+		// ClientOptions configure the PublicClient of every page.
+		ClientOptions []Option
+	}
+
+	// VendorStatus is the last known state of a page.
+	VendorStatus struct {
+		Name string
+		URL  string
+		// Status is the worst of the page indicator, its components and
+		// in progress maintenances, normalized to a ComponentStatus. It is
+		// empty until the page has been read once.
+		Status ComponentStatus
+		// Indicator and Description are the overall status reported by the
+		// page.
+		Indicator   Impact
+		Description string
+		Components  []Component
+		// Incidents are the unresolved incidents of the page.
+		Incidents []Incident
+		// Maintenances are the upcoming and in progress maintenances of the
+		// page.
+		Maintenances []Incident
+		// UpdatedAt is when the page last changed, CheckedAt when it was
+		// last polled.
+		UpdatedAt *time.Time
+		CheckedAt time.Time
+		// Err is the error of the last poll. The other fields then keep the
+		// last state read successfully.
+		Err error
+	}
+
+	// Snapshot is the combined state of every page of an Aggregator.
+	Snapshot struct {
+		// Status is the worst Status of the vendors read at least once,
+		// empty when none was.
+		Status  ComponentStatus
+		Vendors []VendorStatus
+		// Unknown are the pages never read successfully, which Status does
+		// not account for.
+		Unknown []VendorPage
+	}
+
+	// Aggregator polls many public status pages concurrently and combines
+	// their state. Pages are read with conditional requests, so a page that
+	// did not change since the last poll is not downloaded again.
+	Aggregator struct {
+		opts    AggregatorOptions
+		vendors []*vendor
+
+		mu sync.RWMutex
+	}
+
+	vendor struct {
+		page   VendorPage
+		client *PublicClient
+
+		// guarded by Aggregator.mu
+		status     VendorStatus
+		validators CacheValidators
+	}
+)
+
+// statusSeverity orders the component statuses from best to worst.
+var statusSeverity = map[ComponentStatus]int{
+	ComponentStatusOperational:         1,
+	ComponentStatusUnderMaintenance:    2,
+	ComponentStatusDegradedPerformance: 3,
+	ComponentStatusPartialOutage:       4,
+	ComponentStatusMajorOutage:         5,
+}
+
+// indicatorStatus maps the overall indicator of a page to a component status.
+var indicatorStatus = map[Impact]ComponentStatus{
+	ImpactNone:        ComponentStatusOperational,
+	ImpactMaintenance: ComponentStatusUnderMaintenance,
+	ImpactMinor:       ComponentStatusDegradedPerformance,
+	ImpactMajor:       ComponentStatusPartialOutage,
+	ImpactCritical:    ComponentStatusMajorOutage,
+}
+
+// NewAggregator returns an Aggregator watching pages. Call Refresh or Run to
+// poll them.
+func NewAggregator(pages []VendorPage, opts AggregatorOptions) *Aggregator {
+
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+
+	a := &Aggregator{opts: opts}
+	for _, p := range pages {
+		a.vendors = append(a.vendors, &vendor{
+			page:   p,
+			client: NewPublicClient(p.URL, opts.ClientOptions...),
+			status: VendorStatus{Name: p.Name, URL: p.URL},
+		})
+	}
+
+	return a
+}
+
+// Run polls every page each Interval until ctx is done, starting
+// immediately, and returns ctx.Err().
+func (a *Aggregator) Run(ctx context.Context) error {
+
+	ticker := time.NewTicker(a.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		a.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh polls every page once and returns the resulting snapshot. Errors
+// are reported per vendor in the snapshot.
+func (a *Aggregator) Refresh(ctx context.Context) Snapshot {
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, a.opts.Concurrency)
+	)
+	for _, v := range a.vendors {
+		wg.Add(1)
+		go func(v *vendor) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			a.poll(ctx, v)
+		}(v)
+	}
+	wg.Wait()
+
+	return a.Snapshot()
+}
+
+// Snapshot returns the last known state of every page, in the order they
+// were given to NewAggregator.
+func (a *Aggregator) Snapshot() Snapshot {
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var snap Snapshot
+	for _, v := range a.vendors {
+		snap.Vendors = append(snap.Vendors, v.status)
+		if v.status.Status == ComponentStatusEmpty {
+			snap.Unknown = append(snap.Unknown, v.page)
+			continue
+		}
+		snap.Status = worseStatus(snap.Status, v.status.Status)
+	}
+
+	return snap
+}
+
+func (a *Aggregator) poll(ctx context.Context, v *vendor) {
+
+	a.mu.RLock()
+	validators := v.validators
+	a.mu.RUnlock()
+
+	summary, validators, changed, err := v.client.GetSummaryIfChangedWithContext(ctx, validators)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	v.status.CheckedAt = time.Now()
+	v.status.Err = err
+	if err != nil || !changed {
+		return
+	}
+	v.validators = validators
+	v.status = vendorStatus(v.page, summary, v.status.CheckedAt)
+}
+
+func vendorStatus(page VendorPage, s Summary, checked time.Time) VendorStatus {
+
+	vs := VendorStatus{
+		Name:         page.Name,
+		URL:          page.URL,
+		Indicator:    s.Status.Indicator,
+		Description:  s.Status.Description,
+		Components:   s.Components,
+		Incidents:    s.Incidents,
+		Maintenances: s.ScheduledMaintenances,
+		UpdatedAt:    s.Page.UpdatedAt,
+		CheckedAt:    checked,
+	}
+	if vs.Name == "" {
+		vs.Name = s.Page.Name
+	}
+
+	status, ok := indicatorStatus[s.Status.Indicator]
+	if !ok {
+		status = ComponentStatusOperational
+	}
+	for _, c := range s.Components {
+		if !c.Group {
+			status = worseStatus(status, c.Status)
+		}
+	}
+	for _, m := range s.ScheduledMaintenances {
+		if m.Status == IncidentStatusInProgress || m.Status == IncidentStatusVerifying {
+			status = worseStatus(status, ComponentStatusUnderMaintenance)
+		}
+	}
+	vs.Status = status
+
+	return vs
+}
+
+// worseStatus returns the worst of a and b, ignoring unknown statuses.
+func worseStatus(a, b ComponentStatus) ComponentStatus {
+
+	if statusSeverity[b] > statusSeverity[a] {
+		return b
+	}
+	return a
+}
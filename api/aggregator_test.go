@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// summaryJSON returns a summary.json body with the given indicator and
+// component statuses.
+func summaryJSON(name string, indicator Impact, statuses ...ComponentStatus) string {
+
+	components := ""
+	for i, s := range statuses {
+		if i > 0 {
+			components += ","
+		}
+		components += fmt.Sprintf(`{"id":"c%d","name":"Component %d","status":%q}`, i, i, s)
+	}
+	return fmt.Sprintf(`{"page":{"id":"p1","name":%q},"status":{"indicator":%q,"description":"status"},"components":[%s],"incidents":[],"scheduled_maintenances":[]}`,
+		name, indicator, components)
+}
+
+func TestAggregatorConditionalPoll(t *testing.T) {
+
+	var (
+		polls      int32
+		conditions = make(chan string, 4)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditions <- r.Header.Get("If-None-Match") + "|" + r.Header.Get("If-Modified-Since")
+		switch atomic.AddInt32(&polls, 1) {
+		case 1:
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Last-Modified", "Tue, 15 Sep 2020 09:04:18 GMT")
+			w.Write([]byte(summaryJSON("Vendor", ImpactMinor, ComponentStatusOperational)))
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			if r.Header.Get("If-None-Match") != `"v1"` {
+				t.Errorf("If-None-Match = %q, want the ETag of the first response", r.Header.Get("If-None-Match"))
+			}
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	defer srv.Close()
+
+	a := NewAggregator([]VendorPage{{URL: srv.URL}}, AggregatorOptions{
+		ClientOptions: []Option{WithRetryPolicy(RetryPolicy{})},
+	})
+	ctx := context.Background()
+
+	first := a.Refresh(ctx).Vendors[0]
+	if first.Err != nil || first.Status != ComponentStatusDegradedPerformance || first.Name != "Vendor" {
+		t.Fatalf("first poll = %+v", first)
+	}
+	if c := <-conditions; c != "|" {
+		t.Errorf("first poll sent conditions %q, want none", c)
+	}
+
+	failed := a.Refresh(ctx).Vendors[0]
+	if failed.Err == nil || failed.Status != first.Status || !failed.CheckedAt.After(first.CheckedAt) {
+		t.Errorf("failed poll = %+v, want the error and the previous state", failed)
+	}
+	if c := <-conditions; c != `"v1"|Tue, 15 Sep 2020 09:04:18 GMT` {
+		t.Errorf("second poll sent conditions %q", c)
+	}
+
+	unchanged := a.Refresh(ctx).Vendors[0]
+	<-conditions
+	if unchanged.Err != nil {
+		t.Errorf("Err = %v after a 304, want nil", unchanged.Err)
+	}
+	if !unchanged.CheckedAt.After(failed.CheckedAt) {
+		t.Errorf("CheckedAt = %s, want it after %s", unchanged.CheckedAt, failed.CheckedAt)
+	}
+	if unchanged.Status != first.Status || unchanged.Description != first.Description || len(unchanged.Components) != 1 {
+		t.Errorf("state after a 304 = %+v, want the one of the first poll", unchanged)
+	}
+}
+
+func TestAggregatorSnapshotUnknownVendor(t *testing.T) {
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	outage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(summaryJSON("Outage", ImpactCritical, ComponentStatusMajorOutage)))
+	}))
+	defer outage.Close()
+	fine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(summaryJSON("Fine", ImpactNone, ComponentStatusOperational)))
+	}))
+	defer fine.Close()
+
+	pages := []VendorPage{{Name: "down", URL: down.URL}, {URL: outage.URL}, {URL: fine.URL}}
+	a := NewAggregator(pages, AggregatorOptions{
+		ClientOptions: []Option{WithRetryPolicy(RetryPolicy{})},
+	})
+
+	if snap := a.Snapshot(); snap.Status != ComponentStatusEmpty || len(snap.Unknown) != 3 {
+		t.Errorf("snapshot before polling = %+v, want no status and every page unknown", snap)
+	}
+
+	snap := a.Refresh(context.Background())
+	if snap.Status != ComponentStatusMajorOutage {
+		t.Errorf("Status = %q, want %q", snap.Status, ComponentStatusMajorOutage)
+	}
+	if len(snap.Unknown) != 1 || snap.Unknown[0] != pages[0] {
+		t.Errorf("Unknown = %+v, want the page that is down", snap.Unknown)
+	}
+	if snap.Vendors[0].Err == nil {
+		t.Error("the page that is down has no error")
+	}
+}
+
+func TestVendorStatus(t *testing.T) {
+
+	inProgress := Incident{Status: IncidentStatusInProgress}
+	scheduled := Incident{Status: IncidentStatusScheduled}
+
+	tests := []struct {
+		name         string
+		indicator    Impact
+		components   []Component
+		maintenances []Incident
+		want         ComponentStatus
+	}{
+		{name: "none", indicator: ImpactNone, want: ComponentStatusOperational},
+		{name: "minor", indicator: ImpactMinor, want: ComponentStatusDegradedPerformance},
+		{name: "major", indicator: ImpactMajor, want: ComponentStatusPartialOutage},
+		{name: "critical", indicator: ImpactCritical, want: ComponentStatusMajorOutage},
+		{name: "maintenance", indicator: ImpactMaintenance, want: ComponentStatusUnderMaintenance},
+		{name: "unknown indicator", indicator: "unknown", want: ComponentStatusOperational},
+		{
+			name:       "component worse than indicator",
+			indicator:  ImpactMinor,
+			components: []Component{{Status: ComponentStatusOperational}, {Status: ComponentStatusMajorOutage}},
+			want:       ComponentStatusMajorOutage,
+		},
+		{
+			name:       "indicator worse than components",
+			indicator:  ImpactMajor,
+			components: []Component{{Status: ComponentStatusDegradedPerformance}},
+			want:       ComponentStatusPartialOutage,
+		},
+		{
+			name:       "groups ignored",
+			indicator:  ImpactNone,
+			components: []Component{{Status: ComponentStatusOperational}, {Group: true, Status: ComponentStatusMajorOutage}},
+			want:       ComponentStatusOperational,
+		},
+		{
+			name:       "unknown component status ignored",
+			indicator:  ImpactNone,
+			components: []Component{{Status: "unknown"}},
+			want:       ComponentStatusOperational,
+		},
+		{
+			name:         "maintenance in progress",
+			indicator:    ImpactNone,
+			maintenances: []Incident{scheduled, inProgress},
+			want:         ComponentStatusUnderMaintenance,
+		},
+		{
+			name:         "maintenance scheduled",
+			indicator:    ImpactNone,
+			maintenances: []Incident{scheduled},
+			want:         ComponentStatusOperational,
+		},
+		{
+			name:         "outage during maintenance",
+			indicator:    ImpactNone,
+			components:   []Component{{Status: ComponentStatusPartialOutage}},
+			maintenances: []Incident{inProgress},
+			want:         ComponentStatusPartialOutage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Summary{
+				Page:                  PublicPage{Name: "Vendor"},
+				Status:                PageStatus{Indicator: tt.indicator},
+				Components:            tt.components,
+				ScheduledMaintenances: tt.maintenances,
+			}
+			checked := time.Now()
+			vs := vendorStatus(VendorPage{URL: "https://status.example.com"}, s, checked)
+			if vs.Status != tt.want {
+				t.Errorf("Status = %q, want %q", vs.Status, tt.want)
+			}
+			if vs.Name != "Vendor" || vs.Indicator != tt.indicator || !vs.CheckedAt.Equal(checked) {
+				t.Errorf("vendor status = %+v", vs)
+			}
+		})
+	}
+}
+
+func TestWorseStatus(t *testing.T) {
+
+	tests := []struct {
+		a, b, want ComponentStatus
+	}{
+		{ComponentStatusEmpty, ComponentStatusOperational, ComponentStatusOperational},
+		{ComponentStatusOperational, ComponentStatusUnderMaintenance, ComponentStatusUnderMaintenance},
+		{ComponentStatusUnderMaintenance, ComponentStatusDegradedPerformance, ComponentStatusDegradedPerformance},
+		{ComponentStatusPartialOutage, ComponentStatusDegradedPerformance, ComponentStatusPartialOutage},
+		{ComponentStatusMajorOutage, ComponentStatusPartialOutage, ComponentStatusMajorOutage},
+		{ComponentStatusOperational, "unknown", ComponentStatusOperational},
+	}
+
+	for _, tt := range tests {
+		if got := worseStatus(tt.a, tt.b); got != tt.want {
+			t.Errorf("worseStatus(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -1,12 +1,10 @@
 package api
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -32,6 +30,23 @@ type (
 		AutomationEmail    string          `json:"automation_email,omitempty"`
 		StartDate          string          `json:"start_date,omitempty"`
 	}
+
+	reqComponentPayload struct {
+		Component componentPayload `json:"component"`
+	}
+
+	// componentPayload is the body of component create and update calls.
+	// Description, Showcase and OnlyShowIfDegraded are always sent so they
+	// can be cleared or turned off.
+	componentPayload struct {
+		Name               string          `json:"name,omitempty"`
+		Description        string          `json:"description"`
+		Status             ComponentStatus `json:"status,omitempty"`
+		GroupID            string          `json:"group_id,omitempty"`
+		Showcase           bool            `json:"showcase"`
+		OnlyShowIfDegraded bool            `json:"only_show_if_degraded"`
+		StartDate          string          `json:"start_date,omitempty"`
+	}
 )
 
 const (
@@ -47,160 +62,197 @@ func (c ComponentStatus) String() string {
 
 	return string(c)
 }
+
+// GetComponents returns every component of the page, walking all pages of
+// the listing.
 func (s StatusPage) GetComponents() ([]Component, error) {
+	return s.GetComponentsWithContext(context.Background())
+}
 
-	var components []Component
-	url := fmt.Sprintf("%s/v1/pages/%s/components", s.Client.Config.URL, s.Page.ID)
+func (s StatusPage) GetComponentsWithContext(ctx context.Context) ([]Component, error) {
 
-	r, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return components, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return components, err
+	var components []Component
+	it := s.IterateComponents(nil)
+	for it.Next(ctx) {
+		components = append(components, it.Component())
 	}
 
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return components, err
-	}
-	if rsp.StatusCode != 200 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return components, fmt.Errorf("error %s %s", rsp.Status, body)
-	}
+	return components, it.Err()
+
+}
+
+// ListComponents returns a single page of components selected by opts.
+func (s StatusPage) ListComponents(opts *ListOptions) ([]Component, error) {
+	return s.ListComponentsWithContext(context.Background(), opts)
+}
 
-	json.Unmarshal(body, &components)
+func (s StatusPage) ListComponentsWithContext(ctx context.Context, opts *ListOptions) ([]Component, error) {
 
-	return components, nil
+	var components []Component
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/components"),
+		query:      opts.values(),
+		out:        &components,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return components, err
 
 }
 
-func (s StatusPage) UpdateComponent(c Component) (Component, error) {
-	comp := Component{
-		Description:        c.Description,
-		Status:             c.Status,
-		Name:               c.Name,
-		OnlyShowIfDegraded: c.OnlyShowIfDegraded,
-		GroupID:            c.GroupID,
-		Showcase:           c.Showcase,
-		StartDate:          c.StartDate,
-	}
-	url := fmt.Sprintf("%s/v1/pages/%s/components/%s", s.Client.Config.URL, s.Page.ID, c.ID)
+// ComponentIterator walks the components of a page lazily, fetching one page
+// of results at a time.
+type ComponentIterator struct {
+	p   pager
+	buf []Component
+	cur Component
+}
 
-	recComp := ReqComponent{Component: comp}
-	b, err := json.Marshal(recComp)
-	if err != nil {
-		return c, err
-	}
-	r, err := http.NewRequest("PUT", url, bytes.NewBuffer(b))
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	r.Header.Add("Content-Type", "application/json")
-	log.Printf("Atualizando componente %s", c.Name)
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	if rsp.StatusCode != 200 {
-		return c, fmt.Errorf("error %s %s", rsp.Status, body)
+// IterateComponents returns an iterator over all components starting at the
+// page selected by opts.
+func (s StatusPage) IterateComponents(opts *ListOptions) *ComponentIterator {
+
+	it := &ComponentIterator{}
+	it.p = newPager(opts, func(ctx context.Context, o ListOptions) (int, error) {
+		page, err := s.ListComponentsWithContext(ctx, &o)
+		it.buf = append(it.buf, page...)
+		return len(page), err
+	})
+	return it
+}
+
+// Next advances to the next component, returning false when there are no
+// more components or an error occurred.
+func (it *ComponentIterator) Next(ctx context.Context) bool {
+
+	for len(it.buf) == 0 {
+		if !it.p.next(ctx) {
+			return false
+		}
 	}
-	log.Printf("componente %s atualizado", c.Name)
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Component returns the current component.
+func (it *ComponentIterator) Component() Component {
 
-	json.Unmarshal(body, &c)
+	return it.cur
+}
 
-	return c, nil
+// Err returns the error that stopped the iteration, if any.
+func (it *ComponentIterator) Err() error {
 
+	return it.p.err
 }
 
-func (s StatusPage) CreateComponent(c Component) (Component, error) {
+func (s StatusPage) UpdateComponent(c Component) (Component, error) {
+	return s.UpdateComponentWithContext(context.Background(), c)
+}
 
-	url := fmt.Sprintf("%s/v1/pages/%s/components", s.Client.Config.URL, s.Page.ID)
+func (s StatusPage) UpdateComponentWithContext(ctx context.Context, c Component) (Component, error) {
 
-	recComp := ReqComponent{Component: c}
-	b, err := json.Marshal(recComp)
-	log.Print(string(b))
-	log.Print(url)
-	if err != nil {
-		return c, err
-	}
-	r, err := http.NewRequest("POST", url, bytes.NewBuffer(b))
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	r.Header.Add("Content-Type", "application/json")
-	log.Printf("criando componente %s", c.Name)
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
+	err := s.updateComponent(ctx, c.ID, newComponentPayload(c), &c)
+	return c, err
+}
 
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	if rsp.StatusCode != 201 {
-		return c, fmt.Errorf("error %s %s", rsp.Status, body)
-	}
-	log.Printf("componente %s criado com sucesso", c.Name)
-	json.Unmarshal(body, &c)
+func (s StatusPage) CreateComponent(c Component) (Component, error) {
+	return s.CreateComponentWithContext(context.Background(), c)
+}
 
-	return c, nil
+func (s StatusPage) CreateComponentWithContext(ctx context.Context, c Component) (Component, error) {
 
+	err := s.createComponent(ctx, newComponentPayload(c), &c)
+	return c, err
 }
 
 func (s StatusPage) DeleteComponent(c Component) error {
+	return s.DeleteComponentWithContext(context.Background(), c)
+}
 
-	url := fmt.Sprintf("%s/v1/pages/%s/components/%s", s.Client.Config.URL, s.Page.ID, c.ID)
+func (s StatusPage) DeleteComponentWithContext(ctx context.Context, c Component) error {
 
-	r, err := http.NewRequest("DELETE", url, nil)
+	s.Client.logger().Debug("deleting component", "component_id", c.ID, "name", c.Name)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/components/%s", c.ID),
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return err
 	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	log.Printf("deletando componente %s", c.Name)
-	rsp, err := s.Client.httpclient.Do(r)
+	s.Client.logger().Info("component deleted", "component_id", c.ID, "name", c.Name)
+	return nil
+
+}
+
+// createComponent creates the component described by payload, decoding it
+// into out.
+func (s StatusPage) createComponent(ctx context.Context, payload componentPayload, out *Component) error {
+
+	s.Client.logger().Debug("creating component", "name", payload.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/components"),
+		body:   reqComponentPayload{Component: payload},
+		out:    out,
+		expect: http.StatusCreated,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return err
 	}
+	s.Client.logger().Info("component created", "component_id", out.ID, "name", payload.Name)
+
+	return nil
+
+}
 
-	body, err := ioutil.ReadAll(rsp.Body)
+// updateComponent replaces the component with the given id by payload,
+// decoding the result into out.
+func (s StatusPage) updateComponent(ctx context.Context, id string, payload componentPayload, out *Component) error {
+
+	s.Client.logger().Debug("updating component", "component_id", id, "name", payload.Name)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodPut,
+		path:       s.pagePath("/components/%s", id),
+		body:       reqComponentPayload{Component: payload},
+		out:        out,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return err
 	}
-	if rsp.StatusCode != 200 {
-		return fmt.Errorf("error %s %s", rsp.Status, body)
-	}
-	log.Printf("componente deletado com sucesso %s", c.Name)
+	s.Client.logger().Info("component updated", "component_id", id, "name", payload.Name)
+
 	return nil
 
 }
 
-// GetComponentID will check if given Component already exists on gid
+func newComponentPayload(c Component) componentPayload {
+
+	return componentPayload{
+		Name:               c.Name,
+		Description:        c.Description,
+		Status:             c.Status,
+		GroupID:            c.GroupID,
+		Showcase:           c.Showcase,
+		OnlyShowIfDegraded: c.OnlyShowIfDegraded,
+		StartDate:          c.StartDate,
+	}
+}
+
+// GetComponentByName will check if given Component already exists on gid
 func (s StatusPage) GetComponentByName(name string, gid string) (c Component, err error) {
-	components, err := s.GetComponents()
+	return s.GetComponentByNameWithContext(context.Background(), name, gid)
+}
+
+func (s StatusPage) GetComponentByNameWithContext(ctx context.Context, name string, gid string) (c Component, err error) {
+	components, err := s.GetComponentsWithContext(ctx)
 	if err != nil {
-		log.Printf("Error %s", err)
 		return c, err
 	}
 	for _, comp := range components {
@@ -213,6 +265,75 @@ func (s StatusPage) GetComponentByName(name string, gid string) (c Component, er
 
 	}
 
-	return c, fmt.Errorf("unable find component %s", name)
+	return c, fmt.Errorf("unable find component %s: %w", name, ErrNotFound)
+
+}
+
+// ComponentUptime is the availability of a component over a date range, as
+// shown on the page.
+type ComponentUptime struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	RangeStart       string   `json:"range_start"`
+	RangeEnd         string   `json:"range_end"`
+	UptimePercentage float64  `json:"uptime_percentage"`
+	MajorOutage      int      `json:"major_outage"`
+	PartialOutage    int      `json:"partial_outage"`
+	Warnings         []string `json:"warnings"`
+	RelatedEvents    []struct {
+		ID string `json:"id"`
+	} `json:"related_events"`
+}
+
+// MajorOutageDuration returns the time spent in major outage.
+func (u ComponentUptime) MajorOutageDuration() time.Duration {
+
+	return time.Duration(u.MajorOutage) * time.Second
+}
+
+// PartialOutageDuration returns the time spent in partial outage.
+func (u ComponentUptime) PartialOutageDuration() time.Duration {
+
+	return time.Duration(u.PartialOutage) * time.Second
+}
+
+// RelatedIncidentIDs returns the ids of the incidents that affected the
+// uptime.
+func (u ComponentUptime) RelatedIncidentIDs() []string {
+
+	ids := make([]string, 0, len(u.RelatedEvents))
+	for _, e := range u.RelatedEvents {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// GetComponentUptime returns the uptime of the component between the days of
+// start and end. A zero start or end lets the API pick the range.
+func (s StatusPage) GetComponentUptime(componentID string, start, end time.Time) (ComponentUptime, error) {
+	return s.GetComponentUptimeWithContext(context.Background(), componentID, start, end)
+}
+
+func (s StatusPage) GetComponentUptimeWithContext(ctx context.Context, componentID string, start, end time.Time) (ComponentUptime, error) {
+
+	q := url.Values{}
+	if !start.IsZero() {
+		q.Set("start", start.Format("2006-01-02"))
+	}
+	if !end.IsZero() {
+		q.Set("end", end.Format("2006-01-02"))
+	}
+
+	var u ComponentUptime
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/components/%s/uptime", componentID),
+		query:      q,
+		out:        &u,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return u, err
 
 }
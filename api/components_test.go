@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestComponentPayloads(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		call   func(s StatusPage) error
+		method string
+		path   string
+		body   string
+	}{
+		{
+			name: "update component clears description and showcase",
+			call: func(s StatusPage) error {
+				_, err := s.UpdateComponent(Component{ID: "c1", Name: "API", Status: ComponentStatusOperational})
+				return err
+			},
+			method: http.MethodPut,
+			path:   "/v1/pages/p1/components/c1",
+			body:   `{"component":{"name":"API","description":"","status":"operational","showcase":false,"only_show_if_degraded":false}}`,
+		},
+		{
+			name: "create component",
+			call: func(s StatusPage) error {
+				_, err := s.CreateComponent(Component{Name: "API", GroupID: "g1", Showcase: true})
+				return err
+			},
+			method: http.MethodPost,
+			path:   "/v1/pages/p1/components",
+			body:   `{"component":{"name":"API","description":"","group_id":"g1","showcase":true,"only_show_if_degraded":false}}`,
+		},
+		{
+			name: "update group clears description",
+			call: func(s StatusPage) error {
+				_, err := s.UpdateComponentGroup(ComponentGroup{ID: "g1", Name: "Core", Components: []string{"c1", "c2"}})
+				return err
+			},
+			method: http.MethodPut,
+			path:   "/v1/pages/p1/component-groups/g1",
+			body:   `{"description":"","component_group":{"name":"Core","components":["c1","c2"]}}`,
+		},
+		{
+			name: "create group",
+			call: func(s StatusPage) error {
+				_, err := s.CreateComponentGroup(ComponentGroup{Name: "Core", Description: "core services", Components: []string{"c1"}})
+				return err
+			},
+			method: http.MethodPost,
+			path:   "/v1/pages/p1/component-groups",
+			body:   `{"description":"core services","component_group":{"name":"Core","components":["c1"]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.method || r.URL.Path != tt.path {
+					t.Errorf("request = %s %s, want %s %s", r.Method, r.URL.Path, tt.method, tt.path)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Error(err)
+				}
+				if r.Method == http.MethodPost {
+					w.WriteHeader(http.StatusCreated)
+				}
+				w.Write([]byte(`{"id":"x1"}`))
+			}))
+			defer srv.Close()
+
+			if err := tt.call(newTestClient(srv).Page("p1")); err != nil {
+				t.Fatal(err)
+			}
+			var want interface{}
+			if err := json.Unmarshal([]byte(tt.body), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("body = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is matched by errors.Is for every lookup that could not find
+// the requested resource, whether the API answered 404 or a by-name helper
+// found no match.
+var ErrNotFound = errors.New("not found")
+
+// StatusEnhanceYourCalm is the non standard status Statuspage uses, besides
+// 429, to signal that the rate limit of the token was exceeded.
+const StatusEnhanceYourCalm = 420
+
+// APIError is returned whenever the Statuspage API answers with an
+// unexpected status code.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Method     string
+	URL        string
+	// Message is the error reported by Statuspage in the response body, with
+	// multiple messages joined by "; ".
+	Message string
+	// Errors holds each individual message of the response body.
+	Errors []string
+	// RetryAfter is the delay requested by the Retry-After header, zero when
+	// the header is absent.
+	RetryAfter time.Duration
+	// Body is the raw response body. It is left out of Error since it may
+	// be a full HTML page.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+
+	if e.Message == "" {
+		return fmt.Sprintf("%s %s: %s", e.Method, e.URL, e.Status)
+	}
+	return fmt.Sprintf("%s %s: %s %s", e.Method, e.URL, e.Status, e.Message)
+}
+
+// Is makes errors.Is(err, ErrNotFound) report true for 404 responses.
+func (e *APIError) Is(target error) bool {
+
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+func newAPIError(rsp *http.Response, body []byte) *APIError {
+
+	e := &APIError{
+		StatusCode: rsp.StatusCode,
+		Status:     rsp.Status,
+		Body:       body,
+		RetryAfter: parseRetryAfter(rsp.Header.Get("Retry-After")),
+	}
+	if rsp.Request != nil {
+		e.Method = rsp.Request.Method
+		e.URL = rsp.Request.URL.String()
+	}
+
+	// Statuspage reports errors either as {"error": "msg"} or as
+	// {"error": ["msg", ...]}, and a few endpoints use "message" instead.
+	var payload struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		var single string
+		var many []string
+		switch {
+		case json.Unmarshal(payload.Error, &single) == nil && single != "":
+			e.Errors = []string{single}
+		case json.Unmarshal(payload.Error, &many) == nil:
+			e.Errors = many
+		case payload.Message != "":
+			e.Errors = []string{payload.Message}
+		}
+	}
+	e.Message = strings.Join(e.Errors, "; ")
+
+	return e
+}
+
+func parseRetryAfter(v string) time.Duration {
+
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func hasStatus(err error, codes ...int) bool {
+
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	for _, c := range codes {
+		if e.StatusCode == c {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err means the requested resource does not exist.
+func IsNotFound(err error) bool {
+
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsRateLimited reports whether err was caused by exceeding the rate limit.
+func IsRateLimited(err error) bool {
+
+	return hasStatus(err, http.StatusTooManyRequests, StatusEnhanceYourCalm)
+}
+
+// IsUnauthorized reports whether the API rejected the token.
+func IsUnauthorized(err error) bool {
+
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether the token lacks permission for the call.
+func IsForbidden(err error) bool {
+
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsValidationError reports whether the API rejected the request payload.
+func IsValidationError(err error) bool {
+
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a Client talking to srv without rate limiting or
+// retries.
+func newTestClient(srv *httptest.Server, opts ...Option) *Client {
+
+	opts = append([]Option{WithBaseURL(srv.URL), WithRateLimit(0, 0), WithRetryPolicy(RetryPolicy{})}, opts...)
+	return NewClient("token", opts...)
+}
+
+func TestAPIError(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		status     int
+		header     map[string]string
+		body       string
+		errors     []string
+		message    string
+		retryAfter time.Duration
+		notFound   bool
+	}{
+		{
+			name:    "string error",
+			status:  http.StatusUnprocessableEntity,
+			body:    `{"error":"name can't be blank"}`,
+			errors:  []string{"name can't be blank"},
+			message: "name can't be blank",
+		},
+		{
+			name:    "array error",
+			status:  http.StatusBadRequest,
+			body:    `{"error":["name can't be blank","status is invalid"]}`,
+			errors:  []string{"name can't be blank", "status is invalid"},
+			message: "name can't be blank; status is invalid",
+		},
+		{
+			name:    "message",
+			status:  http.StatusUnauthorized,
+			body:    `{"message":"Unauthorized"}`,
+			errors:  []string{"Unauthorized"},
+			message: "Unauthorized",
+		},
+		{
+			name:   "not json",
+			status: http.StatusBadGateway,
+			body:   `<html>bad gateway</html>`,
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			body:     `{"error":"Record not found"}`,
+			errors:   []string{"Record not found"},
+			message:  "Record not found",
+			notFound: true,
+		},
+		{
+			name:       "retry after",
+			status:     http.StatusTooManyRequests,
+			header:     map[string]string{"Retry-After": "7"},
+			body:       `{"error":"Rate limit exceeded"}`,
+			errors:     []string{"Rate limit exceeded"},
+			message:    "Rate limit exceeded",
+			retryAfter: 7 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := newTestClient(srv).GetPages()
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("got error %v, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Method != http.MethodGet || apiErr.URL != srv.URL+"/v1/pages" {
+				t.Errorf("request = %s %s, want GET %s/v1/pages", apiErr.Method, apiErr.URL, srv.URL)
+			}
+			if !reflect.DeepEqual(apiErr.Errors, tt.errors) {
+				t.Errorf("Errors = %q, want %q", apiErr.Errors, tt.errors)
+			}
+			if apiErr.Message != tt.message {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.message)
+			}
+			if apiErr.RetryAfter != tt.retryAfter {
+				t.Errorf("RetryAfter = %s, want %s", apiErr.RetryAfter, tt.retryAfter)
+			}
+			if IsNotFound(err) != tt.notFound {
+				t.Errorf("IsNotFound = %t, want %t", IsNotFound(err), tt.notFound)
+			}
+			if string(apiErr.Body) != tt.body {
+				t.Errorf("Body = %q, want %q", apiErr.Body, tt.body)
+			}
+			if tt.message == "" && strings.Contains(err.Error(), tt.body) {
+				t.Errorf("Error() = %q, want it without the body", err)
+			}
+		})
+	}
+}
@@ -1,11 +1,8 @@
 package api
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
 	"time"
 )
@@ -26,159 +23,205 @@ type (
 		CreatedAt   *time.Time `json:"created_at,omitempty"`
 		UpdatedAt   *time.Time `json:"updated_at,omitempty"`
 	}
+
+	// reqComponentGroupPayload is the body of component group create and
+	// update calls. Unlike ReqComponentGroup it always sends the
+	// description, so an empty one clears it.
+	reqComponentGroupPayload struct {
+		Description    string                `json:"description"`
+		ComponentGroup componentGroupPayload `json:"component_group"`
+	}
+
+	componentGroupPayload struct {
+		Name       string   `json:"name"`
+		Components []string `json:"components"`
+	}
 )
 
+// GetComponentGroups returns every component group of the page, walking all
+// pages of the listing.
 func (s StatusPage) GetComponentGroups() ([]ComponentGroup, error) {
+	return s.GetComponentGroupsWithContext(context.Background())
+}
 
-	groups := []ComponentGroup{}
-	url := fmt.Sprintf("%s/v1/pages/%s/component-groups", s.Client.Config.URL, s.Page.ID)
+func (s StatusPage) GetComponentGroupsWithContext(ctx context.Context) ([]ComponentGroup, error) {
 
-	r, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return groups, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return groups, err
+	groups := []ComponentGroup{}
+	it := s.IterateComponentGroups(nil)
+	for it.Next(ctx) {
+		groups = append(groups, it.ComponentGroup())
 	}
 
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return groups, err
-	}
-	if rsp.StatusCode != 200 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return groups, fmt.Errorf("error %s %s", rsp.Status, body)
-	}
-	json.Unmarshal(body, &groups)
+	return groups, it.Err()
 
-	return groups, nil
+}
 
+// ListComponentGroups returns a single page of component groups selected by
+// opts.
+func (s StatusPage) ListComponentGroups(opts *ListOptions) ([]ComponentGroup, error) {
+	return s.ListComponentGroupsWithContext(context.Background(), opts)
 }
 
-func (s StatusPage) UpdateComponentGroup(c ComponentGroup) (ComponentGroup, error) {
+func (s StatusPage) ListComponentGroupsWithContext(ctx context.Context, opts *ListOptions) ([]ComponentGroup, error) {
 
-	compGroup := ComponentGroup{
-		Components: c.Components,
-		Name:       c.Name,
-	}
-	url := fmt.Sprintf("%s/v1/pages/%s/component-groups/%s", s.Client.Config.URL, s.Page.ID, c.ID)
-	cg := ReqComponentGroup{Description: c.Description, ComponentGroup: compGroup}
+	groups := []ComponentGroup{}
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/component-groups"),
+		query:      opts.values(),
+		out:        &groups,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
 
-	b, err := json.Marshal(&cg)
-	if err != nil {
-		return c, err
-	}
+	return groups, err
 
-	r, err := http.NewRequest("PUT", url, bytes.NewBuffer(b))
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	r.Header.Add("Content-Type", "application/json")
-	log.Printf("atualizando grupo %s", c.Name)
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
+}
 
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	if rsp.StatusCode != 200 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return c, fmt.Errorf("error %s %s", rsp.Status, body)
+// ComponentGroupIterator walks the component groups of a page lazily.
+type ComponentGroupIterator struct {
+	p   pager
+	buf []ComponentGroup
+	cur ComponentGroup
+}
+
+// IterateComponentGroups returns an iterator over all component groups
+// starting at the page selected by opts.
+func (s StatusPage) IterateComponentGroups(opts *ListOptions) *ComponentGroupIterator {
+
+	it := &ComponentGroupIterator{}
+	it.p = newPager(opts, func(ctx context.Context, o ListOptions) (int, error) {
+		page, err := s.ListComponentGroupsWithContext(ctx, &o)
+		it.buf = append(it.buf, page...)
+		return len(page), err
+	})
+	return it
+}
+
+// Next advances to the next group, returning false when there are no more
+// groups or an error occurred.
+func (it *ComponentGroupIterator) Next(ctx context.Context) bool {
+
+	for len(it.buf) == 0 {
+		if !it.p.next(ctx) {
+			return false
+		}
 	}
-	log.Printf("grupo atualizado %s", c.Name)
-	json.Unmarshal(body, &c)
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
 
-	return c, nil
+// ComponentGroup returns the current group.
+func (it *ComponentGroupIterator) ComponentGroup() ComponentGroup {
 
+	return it.cur
 }
 
-func (s StatusPage) CreateComponentGroup(c ComponentGroup) (ComponentGroup, error) {
+// Err returns the error that stopped the iteration, if any.
+func (it *ComponentGroupIterator) Err() error {
 
-	url := fmt.Sprintf("%s/v1/pages/%s/component-groups", s.Client.Config.URL, s.Page.ID)
-	cg := ReqComponentGroup{Description: c.Description, ComponentGroup: c}
+	return it.p.err
+}
 
-	b, err := json.Marshal(&cg)
-	if err != nil {
-		return c, err
-	}
+func (s StatusPage) UpdateComponentGroup(c ComponentGroup) (ComponentGroup, error) {
+	return s.UpdateComponentGroupWithContext(context.Background(), c)
+}
 
-	r, err := http.NewRequest("POST", url, bytes.NewBuffer(b))
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	r.Header.Add("Content-Type", "application/json")
-	log.Printf("criando grupo %s", c.Name)
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return c, err
-	}
-	if rsp.StatusCode != 201 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return c, fmt.Errorf("error %s %s", rsp.Status, body)
-	}
-	log.Printf("grupo %s criado com sucesso", c.Name)
+func (s StatusPage) UpdateComponentGroupWithContext(ctx context.Context, c ComponentGroup) (ComponentGroup, error) {
 
-	json.Unmarshal(body, &c)
+	err := s.updateComponentGroup(ctx, c.ID, newComponentGroupPayload(c), &c)
+	return c, err
+}
 
-	return c, nil
+func (s StatusPage) CreateComponentGroup(c ComponentGroup) (ComponentGroup, error) {
+	return s.CreateComponentGroupWithContext(context.Background(), c)
+}
 
+func (s StatusPage) CreateComponentGroupWithContext(ctx context.Context, c ComponentGroup) (ComponentGroup, error) {
+
+	err := s.createComponentGroup(ctx, newComponentGroupPayload(c), &c)
+	return c, err
 }
 
 func (s StatusPage) DeleteComponentGroups(c ComponentGroup) error {
+	return s.DeleteComponentGroupsWithContext(context.Background(), c)
+}
 
-	url := fmt.Sprintf("%s/v1/pages/%s/component-groups/%s", s.Client.Config.URL, s.Page.ID, c.ID)
+func (s StatusPage) DeleteComponentGroupsWithContext(ctx context.Context, c ComponentGroup) error {
 
-	r, err := http.NewRequest("DELETE", url, nil)
+	s.Client.logger().Debug("deleting component group", "group_id", c.ID, "name", c.Name)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/component-groups/%s", c.ID),
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return err
 	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	log.Printf("deletando grupo %s", c.Name)
-	rsp, err := s.Client.httpclient.Do(r)
+	s.Client.logger().Info("component group deleted", "group_id", c.ID, "name", c.Name)
+	return nil
+
+}
+
+// createComponentGroup creates the group described by payload, decoding it
+// into out.
+func (s StatusPage) createComponentGroup(ctx context.Context, payload reqComponentGroupPayload, out *ComponentGroup) error {
+
+	s.Client.logger().Debug("creating component group", "name", payload.ComponentGroup.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/component-groups"),
+		body:   payload,
+		out:    out,
+		expect: http.StatusCreated,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return err
 	}
+	s.Client.logger().Info("component group created", "group_id", out.ID, "name", payload.ComponentGroup.Name)
+
+	return nil
+
+}
 
-	body, err := ioutil.ReadAll(rsp.Body)
+// updateComponentGroup replaces the group with the given id by payload,
+// decoding the result into out.
+func (s StatusPage) updateComponentGroup(ctx context.Context, id string, payload reqComponentGroupPayload, out *ComponentGroup) error {
+
+	s.Client.logger().Debug("updating component group", "group_id", id, "name", payload.ComponentGroup.Name)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodPut,
+		path:       s.pagePath("/component-groups/%s", id),
+		body:       payload,
+		out:        out,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return err
 	}
-	if rsp.StatusCode != 200 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return fmt.Errorf("error %s %s", rsp.Status, body)
-	}
-	log.Printf("grupo %s deletado com sucesso", c.Name)
+	s.Client.logger().Info("component group updated", "group_id", id, "name", payload.ComponentGroup.Name)
+
 	return nil
 
 }
 
+func newComponentGroupPayload(c ComponentGroup) reqComponentGroupPayload {
+
+	return reqComponentGroupPayload{
+		Description:    c.Description,
+		ComponentGroup: componentGroupPayload{Name: c.Name, Components: c.Components},
+	}
+}
+
 func (s StatusPage) GetComponentGroupByName(name string) (c ComponentGroup, err error) {
-	groups, err := s.GetComponentGroups()
+	return s.GetComponentGroupByNameWithContext(context.Background(), name)
+}
+
+func (s StatusPage) GetComponentGroupByNameWithContext(ctx context.Context, name string) (c ComponentGroup, err error) {
+	groups, err := s.GetComponentGroupsWithContext(ctx)
 	if err != nil {
-		log.Printf("Error %s", err)
 		return c, err
 
 	}
@@ -192,6 +235,6 @@ func (s StatusPage) GetComponentGroupByName(name string) (c ComponentGroup, err
 
 	}
 
-	return c, fmt.Errorf("unable to find group %s", name)
+	return c, fmt.Errorf("unable to find group %s: %w", name, ErrNotFound)
 
 }
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"text/template"
+)
+
+type (
+	ReqIncidentTemplate struct {
+		Template IncidentTemplate `json:"template"`
+	}
+
+	// IncidentTemplate is a reusable incident text. Title and Body may use
+	// text/template actions, which CreateIncidentFromTemplate fills in.
+	IncidentTemplate struct {
+		ID                      string         `json:"id,omitempty"`
+		GroupID                 string         `json:"group_id,omitempty"`
+		Name                    string         `json:"name,omitempty"`
+		Title                   string         `json:"title,omitempty"`
+		Body                    string         `json:"body,omitempty"`
+		UpdateStatus            IncidentStatus `json:"update_status,omitempty"`
+		ShouldTweet             bool           `json:"should_tweet,omitempty"`
+		ShouldSendNotifications bool           `json:"should_send_notifications,omitempty"`
+		Components              []Component    `json:"components,omitempty"`
+		ComponentIDs            []string       `json:"component_ids,omitempty"`
+	}
+)
+
+// GetIncidentTemplates returns every incident template of the page.
+func (s StatusPage) GetIncidentTemplates() ([]IncidentTemplate, error) {
+	return s.GetIncidentTemplatesWithContext(context.Background())
+}
+
+func (s StatusPage) GetIncidentTemplatesWithContext(ctx context.Context) ([]IncidentTemplate, error) {
+
+	var templates []IncidentTemplate
+	p := newPager(nil, func(ctx context.Context, o ListOptions) (int, error) {
+		page, err := s.ListIncidentTemplatesWithContext(ctx, &o)
+		templates = append(templates, page...)
+		return len(page), err
+	})
+	for p.next(ctx) {
+	}
+
+	return templates, p.err
+
+}
+
+// ListIncidentTemplates returns a single page of incident templates.
+func (s StatusPage) ListIncidentTemplates(opts *ListOptions) ([]IncidentTemplate, error) {
+	return s.ListIncidentTemplatesWithContext(context.Background(), opts)
+}
+
+func (s StatusPage) ListIncidentTemplatesWithContext(ctx context.Context, opts *ListOptions) ([]IncidentTemplate, error) {
+
+	var templates []IncidentTemplate
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/incident_templates"),
+		query:      opts.values(),
+		out:        &templates,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return templates, err
+
+}
+
+func (s StatusPage) CreateIncidentTemplate(t IncidentTemplate) (IncidentTemplate, error) {
+	return s.CreateIncidentTemplateWithContext(context.Background(), t)
+}
+
+func (s StatusPage) CreateIncidentTemplateWithContext(ctx context.Context, t IncidentTemplate) (IncidentTemplate, error) {
+
+	tmpl := t
+	tmpl.ID = ""
+	tmpl.Components = nil
+	if len(tmpl.ComponentIDs) == 0 {
+		tmpl.ComponentIDs = componentIDs(t.Components)
+	}
+
+	s.Client.logger().Debug("creating incident template", "name", t.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/incident_templates"),
+		body:   ReqIncidentTemplate{Template: tmpl},
+		out:    &t,
+		expect: http.StatusCreated,
+	})
+	if err != nil {
+		return t, err
+	}
+	s.Client.logger().Info("incident template created", "incident_template_id", t.ID, "name", t.Name)
+
+	return t, nil
+
+}
+
+// GetIncidentTemplateByName returns the incident template called name.
+func (s StatusPage) GetIncidentTemplateByName(name string) (IncidentTemplate, error) {
+	return s.GetIncidentTemplateByNameWithContext(context.Background(), name)
+}
+
+func (s StatusPage) GetIncidentTemplateByNameWithContext(ctx context.Context, name string) (t IncidentTemplate, err error) {
+
+	templates, err := s.GetIncidentTemplatesWithContext(ctx)
+	if err != nil {
+		return t, err
+	}
+	for _, tmpl := range templates {
+		if tmpl.Name == name {
+			return tmpl, nil
+		}
+	}
+
+	return t, fmt.Errorf("unable to find incident template %s: %w", name, ErrNotFound)
+
+}
+
+// CreateIncidentFromTemplate creates an incident named after the template
+// title, with its body, components and status. Title and Body are executed
+// as text/template templates with data, so a template body such as
+// "{{.Service}} is returning errors" can be reused across incidents.
+func (s StatusPage) CreateIncidentFromTemplate(t IncidentTemplate, data interface{}) (Incident, error) {
+	return s.CreateIncidentFromTemplateWithContext(context.Background(), t, data)
+}
+
+func (s StatusPage) CreateIncidentFromTemplateWithContext(ctx context.Context, t IncidentTemplate, data interface{}) (Incident, error) {
+
+	i, err := t.Render(data)
+	if err != nil {
+		return i, err
+	}
+
+	return s.CreateIncidentWithContext(ctx, i)
+
+}
+
+// Render builds the incident described by the template, executing Title and
+// Body with data. Status defaults to IncidentStatusInvestigating when the
+// template has no UpdateStatus.
+func (t IncidentTemplate) Render(data interface{}) (Incident, error) {
+
+	var i Incident
+	name, err := render(t.Name+"/title", t.Title, data)
+	if err != nil {
+		return i, err
+	}
+	body, err := render(t.Name+"/body", t.Body, data)
+	if err != nil {
+		return i, err
+	}
+
+	i.Name = name
+	i.Body = body
+	i.Status = t.UpdateStatus
+	if i.Status == "" {
+		i.Status = IncidentStatusInvestigating
+	}
+	i.ComponentIDs = t.ComponentIDs
+	if len(i.ComponentIDs) == 0 {
+		i.ComponentIDs = componentIDs(t.Components)
+	}
+	i.DeliverNotifications = t.ShouldSendNotifications
+
+	return i, nil
+
+}
+
+func render(name, text string, data interface{}) (string, error) {
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse template %s: %w", name, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("unable to execute template %s: %w", name, err)
+	}
+	return b.String(), nil
+}
+
+func componentIDs(components []Component) []string {
+
+	var ids []string
+	for _, c := range components {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
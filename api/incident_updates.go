@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type (
+	reqIncidentUpdate struct {
+		IncidentUpdate incidentUpdateEdit `json:"incident_update"`
+	}
+
+	// IncidentUpdate is one entry of the timeline of an incident.
+	IncidentUpdate struct {
+		ID                   string              `json:"id,omitempty"`
+		IncidentID           string              `json:"incident_id,omitempty"`
+		Status               IncidentStatus      `json:"status,omitempty"`
+		Body                 string              `json:"body,omitempty"`
+		AffectedComponents   []AffectedComponent `json:"affected_components,omitempty"`
+		DisplayAt            *time.Time          `json:"display_at,omitempty"`
+		DeliverNotifications bool                `json:"deliver_notifications"`
+		WantsTwitterUpdate   bool                `json:"wants_twitter_update"`
+		CustomTweet          string              `json:"custom_tweet,omitempty"`
+		TweetID              string              `json:"tweet_id,omitempty"`
+		TwitterUpdatedAt     *time.Time          `json:"twitter_updated_at,omitempty"`
+		CreatedAt            *time.Time          `json:"created_at,omitempty"`
+		UpdatedAt            *time.Time          `json:"updated_at,omitempty"`
+	}
+
+	// AffectedComponent records the status change of a component announced
+	// by an incident update.
+	AffectedComponent struct {
+		Code      string          `json:"code,omitempty"`
+		Name      string          `json:"name,omitempty"`
+		OldStatus ComponentStatus `json:"old_status,omitempty"`
+		NewStatus ComponentStatus `json:"new_status,omitempty"`
+	}
+
+	// incidentUpdateEdit holds the fields of an incident update the API
+	// allows to change once it was published.
+	incidentUpdateEdit struct {
+		Body                 string     `json:"body,omitempty"`
+		DisplayAt            *time.Time `json:"display_at,omitempty"`
+		DeliverNotifications bool       `json:"deliver_notifications"`
+		WantsTwitterUpdate   bool       `json:"wants_twitter_update"`
+	}
+)
+
+// ListIncidentUpdates returns the timeline of the incident with the given id.
+func (s StatusPage) ListIncidentUpdates(incidentID string) ([]IncidentUpdate, error) {
+	return s.ListIncidentUpdatesWithContext(context.Background(), incidentID)
+}
+
+func (s StatusPage) ListIncidentUpdatesWithContext(ctx context.Context, incidentID string) ([]IncidentUpdate, error) {
+
+	i, err := s.GetIncidentWithContext(ctx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.IncidentUpdates, nil
+
+}
+
+// UpdateIncidentUpdate edits the published update u.ID of incident
+// u.IncidentID. Only Body, DisplayAt, DeliverNotifications and
+// WantsTwitterUpdate can be changed; set DeliverNotifications to false to fix
+// a typo without notifying subscribers again.
+func (s StatusPage) UpdateIncidentUpdate(u IncidentUpdate) (IncidentUpdate, error) {
+	return s.UpdateIncidentUpdateWithContext(context.Background(), u)
+}
+
+func (s StatusPage) UpdateIncidentUpdateWithContext(ctx context.Context, u IncidentUpdate) (IncidentUpdate, error) {
+
+	edit := incidentUpdateEdit{
+		Body:                 u.Body,
+		DisplayAt:            u.DisplayAt,
+		DeliverNotifications: u.DeliverNotifications,
+		WantsTwitterUpdate:   u.WantsTwitterUpdate,
+	}
+
+	s.Client.logger().Debug("updating incident update", "incident_id", u.IncidentID, "incident_update_id", u.ID)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPatch,
+		path:   s.pagePath("/incidents/%s/incident_updates/%s", u.IncidentID, u.ID),
+		body:   reqIncidentUpdate{IncidentUpdate: edit},
+		out:    &u,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return u, err
+	}
+	s.Client.logger().Info("incident update updated", "incident_id", u.IncidentID, "incident_update_id", u.ID)
+
+	return u, nil
+
+}
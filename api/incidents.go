@@ -1,13 +1,9 @@
 package api
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
-	"reflect"
 	"time"
 )
 
@@ -21,47 +17,65 @@ type (
 		Incident `json:"incident,omitempty"`
 	}
 
+	// reqIncident is the body of incident create and update calls. The API
+	// expects components there as a map of component ID to status instead
+	// of the list it returns, so incidentPayload.Components, filled from
+	// Incident.ComponentStatuses, shadows the field of the embedded
+	// Incident.
+	reqIncident struct {
+		Incident incidentPayload `json:"incident,omitempty"`
+	}
+
+	incidentPayload struct {
+		Incident
+		Components map[string]ComponentStatus `json:"components,omitempty"`
+	}
+
 	Incident struct {
-		ID                                        string         `json:"id,omitempty"`
-		Components                                interface{}    `json:"components,omitempty"`
-		ComponentIDs                              []string       `json:"component_ids,omitempty"`
-		CreatedAt                                 *time.Time     `json:"created_at,omitempty"`
-		Impact                                    Impact         `json:"impact,omitempty"`
-		ImpactOverride                            Impact         `json:"impact_override,omitempty"`
-		IncidentUpdates                           []interface{}  `json:"incident_updates,omitempty"`
-		Metadata                                  Metadata       `json:"metadata,omitempty"`
-		MonitoringAt                              *time.Time     `json:"monitoring_at,omitempty"`
-		Name                                      string         `json:"name,omitempty"`
-		PageID                                    string         `json:"page_id,omitempty"`
-		PostmortemBody                            string         `json:"postmortem_body,omitempty"`
-		PostmortemBodyLastUpdatedAt               *time.Time     `json:"postmortem_body_last_updated_at,omitempty"`
-		PostmortemIgnored                         bool           `json:"postmortem_ignored,omitempty"`
-		PostmortemNotifiedSubscribers             bool           `json:"postmortem_notified_subscribers,omitempty"`
-		PostmortemNotifiedTwitter                 bool           `json:"postmortem_notified_twitter,omitempty"`
-		PostmortemPublishedAt                     bool           `json:"postmortem_published_at,omitempty"`
-		ResolvedAt                                *time.Time     `json:"resolved_at,omitempty"`
-		ScheduledAutoCompleted                    bool           `json:"scheduled_auto_completed,omitempty"`
-		ScheduledAutoInProgress                   bool           `json:"scheduled_auto_in_progress,omitempty"`
-		ScheduledAutoTransition                   bool           `json:"scheduled_auto_transition,omitempty"`
-		ScheduledFor                              *time.Time     `json:"scheduled_for,omitempty"`
-		ScheduledRemindPrior                      bool           `json:"scheduled_remind_prior,omitempty"`
-		DeliverNotifications                      bool           `json:"deliver_notifications,omitempty"`
-		AutoTransitionDeliverNotificationsAtEnd   bool           `json:"auto_transition_deliver_notifications_at_end,omitempty"`
-		AutoTransitionDeliverNotificationsAtStart bool           `json:"auto_transition_deliver_notifications_at_start,omitempty"`
-		AutoTransitionToMaintenanceState          bool           `json:"auto_transition_to_maintenance_state,omitempty"`
-		AutoTransitionToOperationalState          bool           `json:"auto_transition_to_operational_state,omitempty"`
-		AutoTweetAtBeginning                      bool           `json:"auto_tweet_at_beginning,omitempty"`
-		AutoTweetOnCompletion                     bool           `json:"auto_tweet_on_completion,omitempty"`
-		AutoTweetOnCreation                       bool           `json:"auto_tweet_on_creation,omitempty"`
-		AutoTweetOneHourBefore                    bool           `json:"auto_tweet_one_hour_before,omitempty"`
-		BackFillDate                              string         `json:"backfill_date,omitempty"`
-		BackFilled                                bool           `json:"backfilled,omitempty"`
-		Body                                      string         `json:"body,omitempty"`
-		ScheduledRemindedAt                       *time.Time     `json:"scheduled_reminded_at,omitempty"`
-		ScheduledUntil                            *time.Time     `json:"scheduled_until,omitempty"`
-		Shortlink                                 string         `json:"shortlink,omitempty"`
-		Status                                    IncidentStatus `json:"status,omitempty"`
-		UpdatedAt                                 *time.Time     `json:"updated_at,omitempty"`
+		ID                                        string           `json:"id,omitempty"`
+		Components                                []Component      `json:"components,omitempty"`
+		ComponentIDs                              []string         `json:"component_ids,omitempty"`
+		CreatedAt                                 *time.Time       `json:"created_at,omitempty"`
+		Impact                                    Impact           `json:"impact,omitempty"`
+		ImpactOverride                            Impact           `json:"impact_override,omitempty"`
+		IncidentUpdates                           []IncidentUpdate `json:"incident_updates,omitempty"`
+		Metadata                                  Metadata         `json:"metadata,omitempty"`
+		MonitoringAt                              *time.Time       `json:"monitoring_at,omitempty"`
+		Name                                      string           `json:"name,omitempty"`
+		PageID                                    string           `json:"page_id,omitempty"`
+		PostmortemBody                            string           `json:"postmortem_body,omitempty"`
+		PostmortemBodyLastUpdatedAt               *time.Time       `json:"postmortem_body_last_updated_at,omitempty"`
+		PostmortemIgnored                         bool             `json:"postmortem_ignored,omitempty"`
+		PostmortemNotifiedSubscribers             bool             `json:"postmortem_notified_subscribers,omitempty"`
+		PostmortemNotifiedTwitter                 bool             `json:"postmortem_notified_twitter,omitempty"`
+		PostmortemPublishedAt                     *time.Time       `json:"postmortem_published_at,omitempty"`
+		ResolvedAt                                *time.Time       `json:"resolved_at,omitempty"`
+		ScheduledAutoCompleted                    bool             `json:"scheduled_auto_completed,omitempty"`
+		ScheduledAutoInProgress                   bool             `json:"scheduled_auto_in_progress,omitempty"`
+		ScheduledAutoTransition                   bool             `json:"scheduled_auto_transition,omitempty"`
+		ScheduledFor                              *time.Time       `json:"scheduled_for,omitempty"`
+		ScheduledRemindPrior                      bool             `json:"scheduled_remind_prior,omitempty"`
+		DeliverNotifications                      bool             `json:"deliver_notifications,omitempty"`
+		AutoTransitionDeliverNotificationsAtEnd   bool             `json:"auto_transition_deliver_notifications_at_end,omitempty"`
+		AutoTransitionDeliverNotificationsAtStart bool             `json:"auto_transition_deliver_notifications_at_start,omitempty"`
+		AutoTransitionToMaintenanceState          bool             `json:"auto_transition_to_maintenance_state,omitempty"`
+		AutoTransitionToOperationalState          bool             `json:"auto_transition_to_operational_state,omitempty"`
+		AutoTweetAtBeginning                      bool             `json:"auto_tweet_at_beginning,omitempty"`
+		AutoTweetOnCompletion                     bool             `json:"auto_tweet_on_completion,omitempty"`
+		AutoTweetOnCreation                       bool             `json:"auto_tweet_on_creation,omitempty"`
+		AutoTweetOneHourBefore                    bool             `json:"auto_tweet_one_hour_before,omitempty"`
+		BackFillDate                              string           `json:"backfill_date,omitempty"`
+		BackFilled                                bool             `json:"backfilled,omitempty"`
+		Body                                      string           `json:"body,omitempty"`
+		ScheduledRemindedAt                       *time.Time       `json:"scheduled_reminded_at,omitempty"`
+		ScheduledUntil                            *time.Time       `json:"scheduled_until,omitempty"`
+		Shortlink                                 string           `json:"shortlink,omitempty"`
+		Status                                    IncidentStatus   `json:"status,omitempty"`
+		UpdatedAt                                 *time.Time       `json:"updated_at,omitempty"`
+
+		// ComponentStatuses sets the status of each affected component, by
+		// component ID, when creating or updating the incident.
+		ComponentStatuses map[string]ComponentStatus `json:"-"`
 	}
 
 	Metadata struct {
@@ -95,73 +109,157 @@ func (i Impact) String() string {
 	return string(i)
 }
 
+// GetIncidents returns every incident of the page, walking all pages of the
+// listing.
 func (s StatusPage) GetIncidents() ([]Incident, error) {
+	return s.GetIncidentsWithContext(context.Background())
+}
 
-	var incidents []Incident
-	url := fmt.Sprintf("%s/v1/pages/%s/incidents", s.Client.Config.URL, s.Page.ID)
+func (s StatusPage) GetIncidentsWithContext(ctx context.Context) ([]Incident, error) {
 
-	r, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return incidents, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return incidents, err
-	}
+	return collectIncidents(ctx, s.IterateIncidents(nil))
+}
 
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return incidents, err
-	}
-	if rsp.StatusCode != 200 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return incidents, fmt.Errorf("error %s %s", rsp.Status, body)
-	}
+// GetUnresolvedIncidents returns every unresolved incident of the page.
+func (s StatusPage) GetUnresolvedIncidents() ([]Incident, error) {
+	return s.GetUnresolvedIncidentsWithContext(context.Background())
+}
 
-	json.Unmarshal(body, &incidents)
+func (s StatusPage) GetUnresolvedIncidentsWithContext(ctx context.Context) ([]Incident, error) {
 
-	return incidents, nil
+	return collectIncidents(ctx, s.IterateUnresolvedIncidents(nil))
+}
 
+// ListIncidents returns a single page of incidents selected by opts.
+func (s StatusPage) ListIncidents(opts *ListOptions) ([]Incident, error) {
+	return s.ListIncidentsWithContext(context.Background(), opts)
 }
 
-func (s StatusPage) GetUnresolvedIncidents() ([]Incident, error) {
+func (s StatusPage) ListIncidentsWithContext(ctx context.Context, opts *ListOptions) ([]Incident, error) {
+
+	return s.listIncidents(ctx, "/incidents", opts)
+}
+
+// ListUnresolvedIncidents returns a single page of unresolved incidents
+// selected by opts.
+func (s StatusPage) ListUnresolvedIncidents(opts *ListOptions) ([]Incident, error) {
+	return s.ListUnresolvedIncidentsWithContext(context.Background(), opts)
+}
+
+func (s StatusPage) ListUnresolvedIncidentsWithContext(ctx context.Context, opts *ListOptions) ([]Incident, error) {
+
+	return s.listIncidents(ctx, "/incidents/unresolved", opts)
+}
+
+func (s StatusPage) listIncidents(ctx context.Context, path string, opts *ListOptions) ([]Incident, error) {
 
 	var incidents []Incident
-	url := fmt.Sprintf("%s/v1/pages/%s/incidents/unresolved", s.Client.Config.URL, s.Page.ID)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath(path),
+		query:      opts.values(),
+		out:        &incidents,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
 
-	r, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return incidents, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return incidents, err
-	}
+	return incidents, err
 
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return incidents, err
+}
+
+// IncidentIterator walks a list of incidents lazily, fetching one page of
+// results at a time.
+type IncidentIterator struct {
+	p   pager
+	buf []Incident
+	cur Incident
+}
+
+// IterateIncidents returns an iterator over all incidents starting at the
+// page selected by opts.
+func (s StatusPage) IterateIncidents(opts *ListOptions) *IncidentIterator {
+
+	return s.iterateIncidents("/incidents", opts)
+}
+
+// IterateUnresolvedIncidents returns an iterator over all unresolved
+// incidents starting at the page selected by opts.
+func (s StatusPage) IterateUnresolvedIncidents(opts *ListOptions) *IncidentIterator {
+
+	return s.iterateIncidents("/incidents/unresolved", opts)
+}
+
+func (s StatusPage) iterateIncidents(path string, opts *ListOptions) *IncidentIterator {
+
+	it := &IncidentIterator{}
+	it.p = newPager(opts, func(ctx context.Context, o ListOptions) (int, error) {
+		page, err := s.listIncidents(ctx, path, &o)
+		it.buf = append(it.buf, page...)
+		return len(page), err
+	})
+	return it
+}
+
+// Next advances to the next incident, returning false when there are no more
+// incidents or an error occurred.
+func (it *IncidentIterator) Next(ctx context.Context) bool {
+
+	for len(it.buf) == 0 {
+		if !it.p.next(ctx) {
+			return false
+		}
 	}
-	if rsp.StatusCode != 200 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return incidents, fmt.Errorf("error %s %s", rsp.Status, body)
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Incident returns the current incident.
+func (it *IncidentIterator) Incident() Incident {
+
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *IncidentIterator) Err() error {
+
+	return it.p.err
+}
+
+func collectIncidents(ctx context.Context, it *IncidentIterator) ([]Incident, error) {
+
+	var incidents []Incident
+	for it.Next(ctx) {
+		incidents = append(incidents, it.Incident())
 	}
 
-	json.Unmarshal(body, &incidents)
+	return incidents, it.Err()
+}
 
-	return incidents, nil
+// GetIncident fetches the incident with the given id, including its updates.
+func (s StatusPage) GetIncident(id string) (Incident, error) {
+	return s.GetIncidentWithContext(context.Background(), id)
+}
+
+func (s StatusPage) GetIncidentWithContext(ctx context.Context, id string) (Incident, error) {
+
+	var i Incident
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/incidents/%s", id),
+		out:        &i,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return i, err
 
 }
 
 func (s StatusPage) UpdateIncident(i Incident) (Incident, error) {
+	return s.UpdateIncidentWithContext(context.Background(), i)
+}
+
+func (s StatusPage) UpdateIncidentWithContext(ctx context.Context, i Incident) (Incident, error) {
 	incident := Incident{
 		Name:                                    i.Name,
 		Status:                                  i.Status,
@@ -183,132 +281,106 @@ func (s StatusPage) UpdateIncident(i Incident) (Incident, error) {
 		BackFillDate:                              i.BackFillDate,
 		BackFilled:                                i.BackFilled,
 		Body:                                      i.Body,
-		Components:                                i.Components,
 		ComponentIDs:                              i.ComponentIDs,
 	}
 
-	url := fmt.Sprintf("%s/v1/pages/%s/incidents/%s", s.Client.Config.URL, s.Page.ID, i.ID)
-
-	recComp := ReqIncident{Incident: incident}
-	b, err := json.Marshal(recComp)
-	if err != nil {
-		return i, err
-	}
-	r, err := http.NewRequest("PUT", url, bytes.NewBuffer(b))
+	s.Client.logger().Debug("updating incident", "incident_id", i.ID, "name", i.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPut,
+		path:   s.pagePath("/incidents/%s", i.ID),
+		body:   reqIncident{Incident: incidentPayload{Incident: incident, Components: i.ComponentStatuses}},
+		out:    &i,
+		expect: http.StatusOK,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return i, err
 	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	r.Header.Add("Content-Type", "application/json")
-	log.Printf("atualizando incidente %s", i.Name)
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return i, err
-	}
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return i, err
-	}
-	if rsp.StatusCode != 200 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return i, fmt.Errorf("error %s %s", rsp.Status, body)
-	}
-	log.Printf("incidente %s atualizado com sucesso", i.Name)
-	json.Unmarshal(body, &i)
+	s.Client.logger().Info("incident updated", "incident_id", i.ID, "name", i.Name)
 
 	return i, nil
 
 }
 
 func (s StatusPage) CreateIncident(i Incident) (Incident, error) {
-	incident := Incident{
-		Name:           i.Name,
-		Status:         i.Status,
-		ImpactOverride: i.ImpactOverride,
-		Body:           i.Body,
-		Components:     i.Components,
-		ComponentIDs:   i.ComponentIDs,
-	}
-	url := fmt.Sprintf("%s/v1/pages/%s/incidents", s.Client.Config.URL, s.Page.ID)
+	return s.CreateIncidentWithContext(context.Background(), i)
+}
 
-	recComp := ReqIncident{Incident: incident}
-	b, err := json.Marshal(recComp)
-	if err != nil {
-		return i, err
-	}
-	fmt.Println(string(b))
-	r, err := http.NewRequest("POST", url, bytes.NewBuffer(b))
-	if err != nil {
-		log.Printf("Error %s", err)
-		return i, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	r.Header.Add("Content-Type", "application/json")
-	log.Printf("criando incidente %s", i.Name)
-	rsp, err := s.Client.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return i, err
+func (s StatusPage) CreateIncidentWithContext(ctx context.Context, i Incident) (Incident, error) {
+	incident := Incident{
+		Name:                                    i.Name,
+		Status:                                  i.Status,
+		ImpactOverride:                          i.ImpactOverride,
+		Body:                                    i.Body,
+		ComponentIDs:                            i.ComponentIDs,
+		Metadata:                                i.Metadata,
+		DeliverNotifications:                    i.DeliverNotifications,
+		ScheduledFor:                            i.ScheduledFor,
+		ScheduledUntil:                          i.ScheduledUntil,
+		ScheduledRemindPrior:                    i.ScheduledRemindPrior,
+		ScheduledAutoInProgress:                 i.ScheduledAutoInProgress,
+		ScheduledAutoCompleted:                  i.ScheduledAutoCompleted,
+		AutoTransitionDeliverNotificationsAtEnd: i.AutoTransitionDeliverNotificationsAtEnd,
+		AutoTransitionDeliverNotificationsAtStart: i.AutoTransitionDeliverNotificationsAtStart,
+		AutoTransitionToMaintenanceState:          i.AutoTransitionToMaintenanceState,
+		AutoTransitionToOperationalState:          i.AutoTransitionToOperationalState,
+		AutoTweetAtBeginning:                      i.AutoTweetAtBeginning,
+		AutoTweetOnCompletion:                     i.AutoTweetOnCompletion,
+		AutoTweetOnCreation:                       i.AutoTweetOnCreation,
+		AutoTweetOneHourBefore:                    i.AutoTweetOneHourBefore,
+		BackFillDate:                              i.BackFillDate,
+		BackFilled:                                i.BackFilled,
 	}
 
-	body, err := ioutil.ReadAll(rsp.Body)
+	s.Client.logger().Debug("creating incident", "name", i.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/incidents"),
+		body:   reqIncident{Incident: incidentPayload{Incident: incident, Components: i.ComponentStatuses}},
+		out:    &i,
+		expect: http.StatusCreated,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return i, err
 	}
-	if rsp.StatusCode != 201 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return i, fmt.Errorf("error %s %s", rsp.Status, body)
-	}
-	log.Printf("incidente %s criado com sucesso", i.Name)
-	json.Unmarshal(body, &i)
+	s.Client.logger().Info("incident created", "incident_id", i.ID, "name", i.Name)
 
 	return i, nil
 
 }
 
 func (s StatusPage) DeleteIncident(i Incident) error {
+	return s.DeleteIncidentWithContext(context.Background(), i)
+}
 
-	url := fmt.Sprintf("%s/v1/pages/%s/incidents/%s", s.Client.Config.URL, s.Page.ID, i.ID)
+func (s StatusPage) DeleteIncidentWithContext(ctx context.Context, i Incident) error {
 
-	r, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", s.Client.Config.Token))
-	log.Printf("deletando incidente %s", i.Name)
-	rsp, err := s.Client.httpclient.Do(r)
+	s.Client.logger().Debug("deleting incident", "incident_id", i.ID, "name", i.Name)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/incidents/%s", i.ID),
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
 	if err != nil {
-		log.Printf("Error %s", err)
 		return err
 	}
-
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return err
-	}
-	if rsp.StatusCode != 200 {
-		log.Printf("Error %s", fmt.Errorf("error %s %s", rsp.Status, body))
-		return fmt.Errorf("error %s %s", rsp.Status, body)
-	}
-	log.Printf("incidente %s deletado com sucesso", i.Name)
+	s.Client.logger().Info("incident deleted", "incident_id", i.ID, "name", i.Name)
 	return nil
 
 }
 
 func (s StatusPage) FilterIncidents(componentID string, status IncidentStatus) ([]Incident, error) {
+	return s.FilterIncidentsWithContext(context.Background(), componentID, status)
+}
+
+func (s StatusPage) FilterIncidentsWithContext(ctx context.Context, componentID string, status IncidentStatus) ([]Incident, error) {
 
 	var incidents []Incident
 
 	if status != IncidentStatusResolved {
-		i, err := s.GetUnresolvedIncidents()
+		i, err := s.GetUnresolvedIncidentsWithContext(ctx)
 		if err != nil {
-			return incidents, fmt.Errorf("unable to get incidents %s", err)
+			return incidents, fmt.Errorf("unable to get incidents %w", err)
 		}
 
 		for _, incident := range i {
@@ -329,9 +401,9 @@ func (s StatusPage) FilterIncidents(componentID string, status IncidentStatus) (
 		}
 	} else {
 
-		i, err := s.GetIncidents()
+		i, err := s.GetIncidentsWithContext(ctx)
 		if err != nil {
-			return incidents, fmt.Errorf("unable to get incidents %s", err)
+			return incidents, fmt.Errorf("unable to get incidents %w", err)
 		}
 
 		for _, incident := range i {
@@ -358,25 +430,23 @@ func (s StatusPage) FilterIncidents(componentID string, status IncidentStatus) (
 }
 
 func (s StatusPage) GetOpenedIncidentByName(name, componentID string) (i Incident, err error) {
+	return s.GetOpenedIncidentByNameWithContext(context.Background(), name, componentID)
+}
+
+func (s StatusPage) GetOpenedIncidentByNameWithContext(ctx context.Context, name, componentID string) (i Incident, err error) {
 	var incidents []Incident
 
-	incidents, err = s.GetUnresolvedIncidents()
+	incidents, err = s.GetUnresolvedIncidentsWithContext(ctx)
 	if err != nil {
-		return i, fmt.Errorf("unable to get incidents %s", err)
+		return i, fmt.Errorf("unable to get incidents %w", err)
 	}
 
 	for _, incident := range incidents {
 
-		s := reflect.ValueOf(incident.Components)
-		comps := make([]interface{}, s.Len())
-		for i := 0; i < s.Len(); i++ {
-			comps[i] = s.Index(i).Interface()
-		}
 		if incident.Name == name {
 
-			for _, component := range comps {
-				c := component.(map[string]interface{})
-				if c["id"] == componentID {
+			for _, c := range incident.Components {
+				if c.ID == componentID {
 
 					return incident, nil
 
@@ -387,6 +457,6 @@ func (s StatusPage) GetOpenedIncidentByName(name, componentID string) (i Inciden
 		}
 
 	}
-	return i, fmt.Errorf("unable to find incident by name %s", name)
+	return i, fmt.Errorf("unable to find incident by name %s: %w", name, ErrNotFound)
 
 }
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestIncidentComponentStatuses(t *testing.T) {
+
+	var body struct {
+		Incident struct {
+			Name         string            `json:"name"`
+			Components   map[string]string `json:"components"`
+			ComponentIDs []string          `json:"component_ids"`
+		} `json:"incident"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"i1","name":"Outage","components":[{"id":"c1","name":"API","status":"major_outage"}]}`))
+	}))
+	defer srv.Close()
+
+	// ReqIncident still embeds Incident
+	req := ReqIncident{Incident: Incident{Name: "Outage"}}
+
+	i, err := newTestClient(srv).Page("p1").CreateIncident(Incident{
+		Name:              req.Name,
+		ComponentIDs:      []string{"c1"},
+		ComponentStatuses: map[string]ComponentStatus{"c1": ComponentStatusMajorOutage},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]string{"c1": "major_outage"}; !reflect.DeepEqual(body.Incident.Components, want) {
+		t.Errorf("sent components %v, want %v", body.Incident.Components, want)
+	}
+	if body.Incident.Name != "Outage" || !reflect.DeepEqual(body.Incident.ComponentIDs, []string{"c1"}) {
+		t.Errorf("sent incident %+v", body.Incident)
+	}
+	if len(i.Components) != 1 || i.Components[0].Status != ComponentStatusMajorOutage {
+		t.Errorf("decoded components %+v", i.Components)
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+// Logger receives the diagnostics of the client as a message followed by
+// alternating key/value pairs. *slog.Logger satisfies it, and adapters for
+// other structured loggers only need these four methods.
+//
+// Requests are reported at debug level with their method, path, status and
+// duration; request and response bodies are only ever passed to Debug. Failed
+// requests are returned to the caller rather than logged above debug level,
+// and the API token is never logged.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// logger returns the configured Logger, which defaults to discarding
+// everything.
+func (c *Client) logger() Logger {
+
+	if c.Config == nil || c.Config.Logger == nil {
+		return nopLogger{}
+	}
+	return c.Config.Logger
+}
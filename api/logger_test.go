@@ -0,0 +1,76 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordLogger keeps every entry logged, formatted as "LEVEL msg args".
+type recordLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *recordLogger) log(level, msg string, args []interface{}) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, fmt.Sprint(level, " ", msg, " ", args))
+}
+
+func (l *recordLogger) Debug(msg string, args ...interface{}) { l.log("DEBUG", msg, args) }
+func (l *recordLogger) Info(msg string, args ...interface{})  { l.log("INFO", msg, args) }
+func (l *recordLogger) Warn(msg string, args ...interface{})  { l.log("WARN", msg, args) }
+func (l *recordLogger) Error(msg string, args ...interface{}) { l.log("ERROR", msg, args) }
+
+func TestFailedRequestLogging(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "html server error", status: http.StatusBadGateway, body: "<html>bad gateway</html>"},
+		{name: "not found", status: http.StatusNotFound, body: `{"error":"Record not found"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			l := &recordLogger{}
+			c := newTestClient(srv,
+				WithLogger(l),
+				WithRetryPolicy(RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+			)
+			if _, err := c.Page("p1").GetComponents(); err == nil {
+				t.Fatal("request succeeded, want an error")
+			}
+
+			failed := false
+			for _, e := range l.entries {
+				if !strings.HasPrefix(e, "DEBUG ") {
+					t.Errorf("logged %q, want debug entries only", e)
+				}
+				if strings.Contains(e, tt.body) && !strings.HasPrefix(e, "DEBUG statuspage response body") {
+					t.Errorf("logged %q, want the body only in the response body entry", e)
+				}
+				if strings.HasPrefix(e, "DEBUG statuspage request failed") && strings.Contains(e, fmt.Sprint(tt.status)) {
+					failed = true
+				}
+			}
+			if !failed {
+				t.Errorf("failure with status %d not logged in %q", tt.status, l.entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMaintenanceWindow is returned by CreateScheduledMaintenance when
+// the window is missing or does not end after it starts.
+var ErrInvalidMaintenanceWindow = errors.New("invalid maintenance window")
+
+// CreateScheduledMaintenance announces the maintenance window described by i,
+// which must have ScheduledFor and ScheduledUntil set with ScheduledUntil
+// after ScheduledFor. Status defaults to IncidentStatusScheduled and
+// ImpactOverride to ImpactMaintenance.
+func (s StatusPage) CreateScheduledMaintenance(i Incident) (Incident, error) {
+	return s.CreateScheduledMaintenanceWithContext(context.Background(), i)
+}
+
+func (s StatusPage) CreateScheduledMaintenanceWithContext(ctx context.Context, i Incident) (Incident, error) {
+
+	if err := validateMaintenanceWindow(i); err != nil {
+		return i, err
+	}
+	if i.Status == "" {
+		i.Status = IncidentStatusScheduled
+	}
+	if i.ImpactOverride == "" {
+		i.ImpactOverride = ImpactMaintenance
+	}
+
+	return s.CreateIncidentWithContext(ctx, i)
+
+}
+
+func validateMaintenanceWindow(i Incident) error {
+
+	switch {
+	case i.ScheduledFor == nil:
+		return fmt.Errorf("scheduled_for is required: %w", ErrInvalidMaintenanceWindow)
+	case i.ScheduledUntil == nil:
+		return fmt.Errorf("scheduled_until is required: %w", ErrInvalidMaintenanceWindow)
+	case !i.ScheduledUntil.After(*i.ScheduledFor):
+		return fmt.Errorf("scheduled_until %s must be after scheduled_for %s: %w",
+			i.ScheduledUntil, i.ScheduledFor, ErrInvalidMaintenanceWindow)
+	}
+	return nil
+}
+
+// ListUpcomingMaintenances returns a page of maintenances that have not
+// started yet.
+func (s StatusPage) ListUpcomingMaintenances(opts *ListOptions) ([]Incident, error) {
+	return s.ListUpcomingMaintenancesWithContext(context.Background(), opts)
+}
+
+func (s StatusPage) ListUpcomingMaintenancesWithContext(ctx context.Context, opts *ListOptions) ([]Incident, error) {
+
+	return s.listIncidents(ctx, "/incidents/upcoming", opts)
+}
+
+// ListActiveMaintenances returns a page of maintenances in progress.
+func (s StatusPage) ListActiveMaintenances(opts *ListOptions) ([]Incident, error) {
+	return s.ListActiveMaintenancesWithContext(context.Background(), opts)
+}
+
+func (s StatusPage) ListActiveMaintenancesWithContext(ctx context.Context, opts *ListOptions) ([]Incident, error) {
+
+	return s.listIncidents(ctx, "/incidents/active_maintenance", opts)
+}
+
+// ListScheduledMaintenances returns a page of all scheduled maintenances.
+func (s StatusPage) ListScheduledMaintenances(opts *ListOptions) ([]Incident, error) {
+	return s.ListScheduledMaintenancesWithContext(context.Background(), opts)
+}
+
+func (s StatusPage) ListScheduledMaintenancesWithContext(ctx context.Context, opts *ListOptions) ([]Incident, error) {
+
+	return s.listIncidents(ctx, "/incidents/scheduled", opts)
+}
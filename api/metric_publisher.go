@@ -0,0 +1,288 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrPublisherStopped is returned by MetricPublisher.Publish once Run has
+// started to stop.
+var ErrPublisherStopped = errors.New("metric publisher stopped")
+
+type (
+	// MetricPoint is a data point of the metric with id MetricID.
+	MetricPoint struct {
+		MetricID string
+		DataPoint
+	}
+
+	// MetricPublisherOptions tunes a MetricPublisher. Zero fields take the
+	// defaults documented on each field.
+	MetricPublisherOptions struct {
+		// FlushInterval is how often buffered points are submitted,
+		// 30 seconds by default.
+		FlushInterval time.Duration
+		// FlushSize submits the buffer as soon as it holds that many
+		// points, MaxDataPointsPerRequest by default.
+		FlushSize int
+		// MaxPending caps the points kept while submissions keep failing;
+		// points received beyond it are dropped and counted in the next
+		// warning logged. Ten times FlushSize by default.
+		MaxPending int
+		// QueueSize is the capacity of the channel returned by Points,
+		// 1000 by default.
+		QueueSize int
+		// MinBackoff and MaxBackoff bound the delay before retrying a failed
+		// submission, 1 second and 5 minutes by default.
+		MinBackoff time.Duration
+		MaxBackoff time.Duration
+		// ShutdownTimeout bounds the final submission made when Run stops,
+		// 10 seconds by default.
+		ShutdownTimeout time.Duration
+	}
+
+	// MetricPublisher submits data points in the background so many
+	// goroutines can report metrics without each making API calls. Points
+	// are buffered per metric, a later point with the same second replacing
+	// the earlier one, and flushed on an interval or once the buffer is
+	// large enough. Failed submissions are kept and retried with backoff.
+	//
+	// Points are accepted through Publish or the Points channel, and are
+	// only submitted while Run is running.
+	MetricPublisher struct {
+		page    StatusPage
+		opts    MetricPublisherOptions
+		points  chan MetricPoint
+		stopped chan struct{}
+
+		// mu is held for reading by Publish while it queues a point and for
+		// writing by Run when it stops, so no point is queued after the
+		// final drain.
+		mu     sync.RWMutex
+		closed bool
+
+		buffer  map[string]map[int64]float64
+		pending int
+		dropped int
+	}
+)
+
+// NewMetricPublisher returns a publisher submitting to the page of s. Call
+// Run to start it.
+func (s StatusPage) NewMetricPublisher(opts MetricPublisherOptions) *MetricPublisher {
+
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 30 * time.Second
+	}
+	if opts.FlushSize <= 0 {
+		opts.FlushSize = MaxDataPointsPerRequest
+	}
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = 10 * opts.FlushSize
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 5 * time.Minute
+	}
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = 10 * time.Second
+	}
+
+	return &MetricPublisher{
+		page:    s,
+		opts:    opts,
+		points:  make(chan MetricPoint, opts.QueueSize),
+		stopped: make(chan struct{}),
+		buffer:  map[string]map[int64]float64{},
+	}
+}
+
+// Points returns the channel feeding the publisher. Sending blocks when the
+// queue is full, and points sent once Run started to stop are not submitted;
+// use Publish to be told.
+func (p *MetricPublisher) Points() chan<- MetricPoint {
+
+	return p.points
+}
+
+// Publish queues value for the metric with the given id at time t. It blocks
+// while the queue is full, until ctx is done or the publisher stopped, and
+// returns ErrPublisherStopped once Run started to stop.
+func (p *MetricPublisher) Publish(ctx context.Context, metricID string, value float64, t time.Time) error {
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPublisherStopped
+	}
+	pt := MetricPoint{MetricID: metricID, DataPoint: DataPoint{Timestamp: t, Value: value}}
+	select {
+	case p.points <- pt:
+		return nil
+	case <-p.stopped:
+		return ErrPublisherStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run buffers and submits points until ctx is done. It then stops accepting
+// points, drains the queue, makes a last submission bounded by
+// ShutdownTimeout and returns its error. Run must only be called once.
+func (p *MetricPublisher) Run(ctx context.Context) error {
+
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+
+	var (
+		failures int
+		retry    *time.Timer
+		retryC   <-chan time.Time
+	)
+	defer func() {
+		if retry != nil {
+			retry.Stop()
+		}
+	}()
+	flush := func() {
+		if err := p.flush(ctx); err != nil {
+			failures++
+			delay := RetryPolicy{MinBackoff: p.opts.MinBackoff, MaxBackoff: p.opts.MaxBackoff}.delay(failures - 1)
+			p.page.Client.logger().Warn("metric submission failed",
+				"pending", p.pending,
+				"dropped", p.dropped,
+				"failures", failures,
+				"retry_in", delay,
+				"error", err,
+			)
+			p.dropped = 0
+			retry = time.NewTimer(delay)
+			retryC = retry.C
+			return
+		}
+		failures = 0
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return p.shutdown()
+		case pt := <-p.points:
+			p.add(pt)
+			// while backing off only the retry timer flushes
+			if p.pending >= p.opts.FlushSize && failures == 0 {
+				flush()
+			}
+		case <-ticker.C:
+			if failures == 0 {
+				flush()
+			}
+		case <-retryC:
+			retryC = nil
+			flush()
+		}
+	}
+}
+
+func (p *MetricPublisher) shutdown() error {
+
+	// wake up Publish calls blocked on a full queue, then wait for those in
+	// progress so nothing is queued once drained
+	close(p.stopped)
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+drain:
+	for {
+		select {
+		case pt := <-p.points:
+			p.add(pt)
+		default:
+			break drain
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.ShutdownTimeout)
+	defer cancel()
+	err := p.flush(ctx)
+	if err != nil {
+		p.page.Client.logger().Warn("final metric submission failed", "dropped", p.pending+p.dropped)
+	}
+	return err
+}
+
+func (p *MetricPublisher) add(pt MetricPoint) {
+
+	points, ok := p.buffer[pt.MetricID]
+	if !ok {
+		points = map[int64]float64{}
+		p.buffer[pt.MetricID] = points
+	}
+	ts := pt.Timestamp.Unix()
+	if _, ok := points[ts]; ok {
+		points[ts] = pt.Value
+		return
+	}
+	if p.pending >= p.opts.MaxPending {
+		p.dropped++
+		return
+	}
+	points[ts] = pt.Value
+	p.pending++
+}
+
+// flush submits the buffer one batch at a time. When a batch fails, its
+// points and those of the batches not sent yet are put back; batches already
+// accepted are not sent again.
+func (p *MetricPublisher) flush(ctx context.Context) error {
+
+	if p.pending == 0 {
+		return nil
+	}
+	buffer := p.buffer
+	p.buffer, p.pending = map[string]map[int64]float64{}, 0
+
+	data := make(map[string][]DataPoint, len(buffer))
+	for id, points := range buffer {
+		for ts, v := range points {
+			data[id] = append(data[id], DataPoint{Timestamp: time.Unix(ts, 0), Value: v})
+		}
+		sort.Slice(data[id], func(i, j int) bool {
+			return data[id][i].Timestamp.Before(data[id][j].Timestamp)
+		})
+	}
+
+	batches := batchDataPoints(data, MaxDataPointsPerRequest)
+	for i, batch := range batches {
+		if err := p.page.submitBatch(ctx, batch); err != nil {
+			for _, unsent := range batches[i:] {
+				p.restore(unsent)
+			}
+			return err
+		}
+	}
+	p.page.Client.logger().Debug("metric points submitted", "metrics", len(data))
+
+	return nil
+}
+
+// restore puts the points of a batch that was not submitted back into the
+// buffer.
+func (p *MetricPublisher) restore(batch map[string][]dataPoint) {
+
+	for id, points := range batch {
+		for _, pt := range points {
+			p.add(MetricPoint{MetricID: id, DataPoint: DataPoint{Timestamp: time.Unix(pt.Timestamp, 0), Value: pt.Value}})
+		}
+	}
+}
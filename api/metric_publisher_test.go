@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMetricPublisherFlushKeepsUnsentBatches(t *testing.T) {
+
+	var (
+		sizes []int
+		fail  = map[int]bool{2: true}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body reqMetricsData
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		n := 0
+		for _, points := range body.Data {
+			n += len(points)
+		}
+		sizes = append(sizes, n)
+		if fail[len(sizes)] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	p := newTestClient(srv).Page("p1").NewMetricPublisher(MetricPublisherOptions{MaxPending: 10000})
+	start := time.Unix(1600000000, 0)
+	for i := 0; i < MaxDataPointsPerRequest+500; i++ {
+		p.add(MetricPoint{MetricID: "m1", DataPoint: DataPoint{Timestamp: start.Add(time.Duration(i) * time.Second), Value: float64(i)}})
+	}
+
+	if err := p.flush(context.Background()); err == nil {
+		t.Fatal("flush succeeded, want the error of the second batch")
+	}
+	if p.pending != 500 {
+		t.Fatalf("%d points pending after the failure, want 500", p.pending)
+	}
+
+	if err := p.flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{MaxDataPointsPerRequest, 500, 500}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("submitted batches of %v points, want %v", sizes, want)
+	}
+	if p.pending != 0 {
+		t.Errorf("%d points pending, want 0", p.pending)
+	}
+}
+
+func TestMetricPublisherStopsAcceptingBeforeFinalFlush(t *testing.T) {
+
+	var (
+		flushing = make(chan struct{})
+		release  = make(chan struct{})
+		received = make(chan int, 1)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body reqMetricsData
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		close(flushing)
+		<-release
+		received <- len(body.Data["m1"])
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	p := newTestClient(srv).Page("p1").NewMetricPublisher(MetricPublisherOptions{FlushInterval: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	start := time.Unix(1600000000, 0)
+	if err := p.Publish(context.Background(), "m1", 1, start); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	<-flushing
+
+	// the final flush is in progress, a point published now would be lost
+	pctx, pcancel := context.WithTimeout(context.Background(), time.Second)
+	defer pcancel()
+	if err := p.Publish(pctx, "m1", 2, start.Add(time.Second)); err != ErrPublisherStopped {
+		t.Errorf("Publish during the final flush = %v, want ErrPublisherStopped", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if n := <-received; n != 1 {
+		t.Errorf("final flush sent %d points, want 1", n)
+	}
+	if err := p.Publish(context.Background(), "m1", 3, start.Add(2*time.Second)); err != ErrPublisherStopped {
+		t.Errorf("Publish after Run = %v, want ErrPublisherStopped", err)
+	}
+}
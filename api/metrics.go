@@ -0,0 +1,444 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// MaxDataPointsPerRequest bounds the number of data points SubmitData sends
+// in a single request; larger submissions are split.
+const MaxDataPointsPerRequest = 3000
+
+type (
+	MetricProviderType string
+
+	ReqMetricProvider struct {
+		MetricProvider MetricProvider `json:"metrics_provider"`
+	}
+
+	ReqMetric struct {
+		Metric Metric `json:"metric"`
+	}
+
+	// MetricProvider is the source of the metrics of a page. Email,
+	// Password, APIKey, APIToken and ApplicationKey are only sent when
+	// creating the provider and never returned by the API.
+	MetricProvider struct {
+		ID                string             `json:"id,omitempty"`
+		PageID            string             `json:"page_id,omitempty"`
+		Type              MetricProviderType `json:"type,omitempty"`
+		Disabled          bool               `json:"disabled,omitempty"`
+		MetricBaseURI     string             `json:"metric_base_uri,omitempty"`
+		Email             string             `json:"email,omitempty"`
+		Password          string             `json:"password,omitempty"`
+		APIKey            string             `json:"api_key,omitempty"`
+		APIToken          string             `json:"api_token,omitempty"`
+		ApplicationKey    string             `json:"application_key,omitempty"`
+		LastRevalidatedAt *time.Time         `json:"last_revalidated_at,omitempty"`
+		CreatedAt         *time.Time         `json:"created_at,omitempty"`
+		UpdatedAt         *time.Time         `json:"updated_at,omitempty"`
+	}
+
+	Metric struct {
+		ID                 string     `json:"id,omitempty"`
+		MetricsProviderID  string     `json:"metrics_provider_id,omitempty"`
+		MetricIdentifier   string     `json:"metric_identifier,omitempty"`
+		Name               string     `json:"name,omitempty"`
+		Display            bool       `json:"display,omitempty"`
+		TooltipDescription string     `json:"tooltip_description,omitempty"`
+		Backfilled         bool       `json:"backfilled,omitempty"`
+		YAxisMin           *float64   `json:"y_axis_min,omitempty"`
+		YAxisMax           *float64   `json:"y_axis_max,omitempty"`
+		YAxisHidden        bool       `json:"y_axis_hidden,omitempty"`
+		Suffix             string     `json:"suffix,omitempty"`
+		DecimalPlaces      int        `json:"decimal_places,omitempty"`
+		MostRecentDataAt   *time.Time `json:"most_recent_data_at,omitempty"`
+		LastFetchedAt      *time.Time `json:"last_fetched_at,omitempty"`
+		CreatedAt          *time.Time `json:"created_at,omitempty"`
+		UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+	}
+
+	// DataPoint is a value of a metric at a point in time.
+	DataPoint struct {
+		Timestamp time.Time
+		Value     float64
+	}
+
+	// dataPoint is the wire format of DataPoint, with the timestamp in unix
+	// seconds.
+	dataPoint struct {
+		Timestamp int64   `json:"timestamp"`
+		Value     float64 `json:"value"`
+	}
+
+	reqMetricsData struct {
+		Data map[string][]dataPoint `json:"data"`
+	}
+)
+
+const (
+	MetricProviderPingdom  MetricProviderType = "Pingdom"
+	MetricProviderNewRelic MetricProviderType = "NewRelic"
+	MetricProviderLibrato  MetricProviderType = "Librato"
+	MetricProviderDatadog  MetricProviderType = "Datadog"
+	MetricProviderSelf     MetricProviderType = "Self"
+)
+
+func (t MetricProviderType) String() string {
+
+	return string(t)
+}
+
+func (s StatusPage) GetMetricProviders() ([]MetricProvider, error) {
+	return s.GetMetricProvidersWithContext(context.Background())
+}
+
+func (s StatusPage) GetMetricProvidersWithContext(ctx context.Context) ([]MetricProvider, error) {
+
+	var providers []MetricProvider
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/metrics_providers"),
+		out:        &providers,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return providers, err
+
+}
+
+func (s StatusPage) GetMetricProvider(id string) (MetricProvider, error) {
+	return s.GetMetricProviderWithContext(context.Background(), id)
+}
+
+func (s StatusPage) GetMetricProviderWithContext(ctx context.Context, id string) (MetricProvider, error) {
+
+	var p MetricProvider
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/metrics_providers/%s", id),
+		out:        &p,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return p, err
+
+}
+
+func (s StatusPage) CreateMetricProvider(p MetricProvider) (MetricProvider, error) {
+	return s.CreateMetricProviderWithContext(context.Background(), p)
+}
+
+func (s StatusPage) CreateMetricProviderWithContext(ctx context.Context, p MetricProvider) (MetricProvider, error) {
+
+	provider := MetricProvider{
+		Type:           p.Type,
+		MetricBaseURI:  p.MetricBaseURI,
+		Email:          p.Email,
+		Password:       p.Password,
+		APIKey:         p.APIKey,
+		APIToken:       p.APIToken,
+		ApplicationKey: p.ApplicationKey,
+	}
+
+	s.Client.logger().Debug("creating metric provider", "type", p.Type.String())
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/metrics_providers"),
+		body:   ReqMetricProvider{MetricProvider: provider},
+		out:    &p,
+		expect: http.StatusCreated,
+	})
+	if err != nil {
+		return p, err
+	}
+	s.Client.logger().Info("metric provider created", "metric_provider_id", p.ID, "type", p.Type.String())
+
+	return p, nil
+
+}
+
+// UpdateMetricProvider changes the type and base URI of the provider.
+func (s StatusPage) UpdateMetricProvider(p MetricProvider) (MetricProvider, error) {
+	return s.UpdateMetricProviderWithContext(context.Background(), p)
+}
+
+func (s StatusPage) UpdateMetricProviderWithContext(ctx context.Context, p MetricProvider) (MetricProvider, error) {
+
+	provider := MetricProvider{
+		Type:          p.Type,
+		MetricBaseURI: p.MetricBaseURI,
+	}
+
+	s.Client.logger().Debug("updating metric provider", "metric_provider_id", p.ID)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPatch,
+		path:   s.pagePath("/metrics_providers/%s", p.ID),
+		body:   ReqMetricProvider{MetricProvider: provider},
+		out:    &p,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return p, err
+	}
+	s.Client.logger().Info("metric provider updated", "metric_provider_id", p.ID)
+
+	return p, nil
+
+}
+
+func (s StatusPage) DeleteMetricProvider(p MetricProvider) error {
+	return s.DeleteMetricProviderWithContext(context.Background(), p)
+}
+
+func (s StatusPage) DeleteMetricProviderWithContext(ctx context.Context, p MetricProvider) error {
+
+	s.Client.logger().Debug("deleting metric provider", "metric_provider_id", p.ID)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/metrics_providers/%s", p.ID),
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+	s.Client.logger().Info("metric provider deleted", "metric_provider_id", p.ID)
+	return nil
+
+}
+
+// GetMetrics returns the metrics of every provider of the page.
+func (s StatusPage) GetMetrics() ([]Metric, error) {
+	return s.GetMetricsWithContext(context.Background())
+}
+
+func (s StatusPage) GetMetricsWithContext(ctx context.Context) ([]Metric, error) {
+
+	var metrics []Metric
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/metrics"),
+		out:        &metrics,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return metrics, err
+
+}
+
+// GetProviderMetrics returns the metrics of the provider with the given id.
+func (s StatusPage) GetProviderMetrics(providerID string) ([]Metric, error) {
+	return s.GetProviderMetricsWithContext(context.Background(), providerID)
+}
+
+func (s StatusPage) GetProviderMetricsWithContext(ctx context.Context, providerID string) ([]Metric, error) {
+
+	var metrics []Metric
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/metrics_providers/%s/metrics", providerID),
+		out:        &metrics,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return metrics, err
+
+}
+
+func (s StatusPage) GetMetric(id string) (Metric, error) {
+	return s.GetMetricWithContext(context.Background(), id)
+}
+
+func (s StatusPage) GetMetricWithContext(ctx context.Context, id string) (Metric, error) {
+
+	var m Metric
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/metrics/%s", id),
+		out:        &m,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return m, err
+
+}
+
+// CreateMetric adds m to the provider m.MetricsProviderID.
+func (s StatusPage) CreateMetric(m Metric) (Metric, error) {
+	return s.CreateMetricWithContext(context.Background(), m)
+}
+
+func (s StatusPage) CreateMetricWithContext(ctx context.Context, m Metric) (Metric, error) {
+
+	metric := Metric{
+		Name:               m.Name,
+		MetricIdentifier:   m.MetricIdentifier,
+		Display:            m.Display,
+		TooltipDescription: m.TooltipDescription,
+		YAxisMin:           m.YAxisMin,
+		YAxisMax:           m.YAxisMax,
+		YAxisHidden:        m.YAxisHidden,
+		Suffix:             m.Suffix,
+		DecimalPlaces:      m.DecimalPlaces,
+	}
+
+	s.Client.logger().Debug("creating metric", "metric_provider_id", m.MetricsProviderID, "name", m.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/metrics_providers/%s/metrics", m.MetricsProviderID),
+		body:   ReqMetric{Metric: metric},
+		out:    &m,
+		expect: http.StatusCreated,
+	})
+	if err != nil {
+		return m, err
+	}
+	s.Client.logger().Info("metric created", "metric_id", m.ID, "name", m.Name)
+
+	return m, nil
+
+}
+
+// UpdateMetric changes the name and identifier of the metric.
+func (s StatusPage) UpdateMetric(m Metric) (Metric, error) {
+	return s.UpdateMetricWithContext(context.Background(), m)
+}
+
+func (s StatusPage) UpdateMetricWithContext(ctx context.Context, m Metric) (Metric, error) {
+
+	metric := Metric{
+		Name:             m.Name,
+		MetricIdentifier: m.MetricIdentifier,
+	}
+
+	s.Client.logger().Debug("updating metric", "metric_id", m.ID, "name", m.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPatch,
+		path:   s.pagePath("/metrics/%s", m.ID),
+		body:   ReqMetric{Metric: metric},
+		out:    &m,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return m, err
+	}
+	s.Client.logger().Info("metric updated", "metric_id", m.ID, "name", m.Name)
+
+	return m, nil
+
+}
+
+func (s StatusPage) DeleteMetric(m Metric) error {
+	return s.DeleteMetricWithContext(context.Background(), m)
+}
+
+func (s StatusPage) DeleteMetricWithContext(ctx context.Context, m Metric) error {
+
+	s.Client.logger().Debug("deleting metric", "metric_id", m.ID, "name", m.Name)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/metrics/%s", m.ID),
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+	s.Client.logger().Info("metric deleted", "metric_id", m.ID, "name", m.Name)
+	return nil
+
+}
+
+// ResetMetricData deletes every data point of the metric.
+func (s StatusPage) ResetMetricData(m Metric) error {
+	return s.ResetMetricDataWithContext(context.Background(), m)
+}
+
+func (s StatusPage) ResetMetricDataWithContext(ctx context.Context, m Metric) error {
+
+	s.Client.logger().Debug("resetting metric data", "metric_id", m.ID)
+	return s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/metrics/%s/data", m.ID),
+		idempotent: true,
+	})
+
+}
+
+// SubmitDataPoints publishes points for the metric with the given id.
+func (s StatusPage) SubmitDataPoints(metricID string, points []DataPoint) error {
+	return s.SubmitDataPointsWithContext(context.Background(), metricID, points)
+}
+
+func (s StatusPage) SubmitDataPointsWithContext(ctx context.Context, metricID string, points []DataPoint) error {
+
+	return s.SubmitDataWithContext(ctx, map[string][]DataPoint{metricID: points})
+}
+
+// SubmitData publishes the data points of several metrics, keyed by metric
+// id. Points are sent in as few requests as MaxDataPointsPerRequest allows;
+// when a request fails the remaining ones are not sent.
+func (s StatusPage) SubmitData(data map[string][]DataPoint) error {
+	return s.SubmitDataWithContext(context.Background(), data)
+}
+
+func (s StatusPage) SubmitDataWithContext(ctx context.Context, data map[string][]DataPoint) error {
+
+	for _, batch := range batchDataPoints(data, MaxDataPointsPerRequest) {
+		if err := s.submitBatch(ctx, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+// submitBatch sends one batch built by batchDataPoints in a single request.
+func (s StatusPage) submitBatch(ctx context.Context, batch map[string][]dataPoint) error {
+
+	s.Client.logger().Debug("submitting metric data", "metrics", len(batch))
+	return s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/metrics/data"),
+		body:   reqMetricsData{Data: batch},
+	})
+}
+
+// batchDataPoints splits data into batches of at most size points, in metric
+// id order, splitting the points of a metric across batches when needed.
+func batchDataPoints(data map[string][]DataPoint, size int) []map[string][]dataPoint {
+
+	ids := make([]string, 0, len(data))
+	for id := range data {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var (
+		batches []map[string][]dataPoint
+		batch   = map[string][]dataPoint{}
+		n       int
+	)
+	for _, id := range ids {
+		for _, p := range data[id] {
+			if n == size {
+				batches = append(batches, batch)
+				batch, n = map[string][]dataPoint{}, 0
+			}
+			batch[id] = append(batch[id], dataPoint{Timestamp: p.Timestamp.Unix(), Value: p.Value})
+			n++
+		}
+	}
+	if n > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
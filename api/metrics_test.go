@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubmitDataBatches(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		points   []int // points per metric
+		requests int
+	}{
+		{name: "single metric below the limit", points: []int{10}, requests: 1},
+		{name: "exactly the limit", points: []int{1000, 2000}, requests: 1},
+		{name: "one point over the limit", points: []int{1000, 2000, 1}, requests: 2},
+		{name: "metric split across batches", points: []int{2500, 2500}, requests: 2},
+		{name: "metric spanning three batches", points: []int{1, 7000, 1}, requests: 3},
+		{name: "many metrics", points: []int{900, 900, 900, 900, 900, 900, 900}, requests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu       sync.Mutex
+				requests int
+				received = map[string]int{}
+				last     = map[string]int64{}
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body reqMetricsData
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				requests++
+				n := 0
+				for id, points := range body.Data {
+					for _, p := range points {
+						if p.Timestamp <= last[id] {
+							t.Errorf("point %s/%d sent after %d", id, p.Timestamp, last[id])
+						}
+						last[id] = p.Timestamp
+						received[fmt.Sprintf("%s/%d/%v", id, p.Timestamp, p.Value)]++
+						n++
+					}
+				}
+				if n > MaxDataPointsPerRequest {
+					t.Errorf("request %d holds %d points, want at most %d", requests, n, MaxDataPointsPerRequest)
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer srv.Close()
+
+			start := time.Unix(1600000000, 0)
+			data := map[string][]DataPoint{}
+			sent := map[string]int{}
+			for m, n := range tt.points {
+				id := fmt.Sprintf("m%d", m)
+				for i := 0; i < n; i++ {
+					p := DataPoint{Timestamp: start.Add(time.Duration(i) * time.Second), Value: float64(m*10000 + i)}
+					data[id] = append(data[id], p)
+					sent[fmt.Sprintf("%s/%d/%v", id, p.Timestamp.Unix(), p.Value)]++
+				}
+			}
+
+			if err := newTestClient(srv).Page("p1").SubmitData(data); err != nil {
+				t.Fatal(err)
+			}
+
+			if requests != tt.requests {
+				t.Errorf("sent %d requests, want %d", requests, tt.requests)
+			}
+			if len(received) != len(sent) {
+				t.Errorf("received %d distinct points, want %d", len(received), len(sent))
+			}
+			for k, n := range received {
+				if n != 1 || sent[k] != 1 {
+					t.Errorf("point %s received %d times, sent %d times", k, n, sent[k])
+				}
+			}
+		})
+	}
+}
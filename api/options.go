@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the Statuspage manage API.
+	DefaultBaseURL = "https://api.statuspage.io"
+	// DefaultUserAgent is sent with every request unless overridden with
+	// WithUserAgent.
+	DefaultUserAgent = "atlassiansp"
+)
+
+// Option configures a Client built by NewClient.
+type Option func(*settings)
+
+type settings struct {
+	config     Config
+	httpClient *http.Client
+	transport  http.RoundTripper
+}
+
+// NewClient returns a Client authenticating with token. Without options it
+// talks to DefaultBaseURL using DefaultRetryPolicy and DefaultRateLimit and
+// logs nothing.
+func NewClient(token string, opts ...Option) *Client {
+
+	s := settings{
+		config: Config{
+			URL:       DefaultBaseURL,
+			Token:     token,
+			UserAgent: DefaultUserAgent,
+			Retry:     DefaultRetryPolicy,
+			RateLimit: DefaultRateLimit,
+			RateBurst: DefaultRateBurst,
+		},
+	}
+	for _, o := range opts {
+		o(&s)
+	}
+
+	// copy the given client so applying the timeout or transport does not
+	// change it for its other users
+	hc := &http.Client{}
+	if s.httpClient != nil {
+		c := *s.httpClient
+		hc = &c
+	}
+	if s.transport != nil {
+		hc.Transport = s.transport
+	}
+	if s.config.Timeout > 0 {
+		hc.Timeout = s.config.Timeout
+	}
+
+	c := s.config
+	return &Client{
+		Config:     &c,
+		httpclient: hc,
+		limiter:    newRateLimiter(c.RateLimit, c.RateBurst),
+	}
+}
+
+// WithHTTPClient makes the client send its requests through hc, e.g. to reuse
+// a client configured with a proxy or custom TLS settings.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(s *settings) {
+		s.httpClient = hc
+	}
+}
+
+// WithTransport sets the RoundTripper used to send requests.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(s *settings) {
+		s.transport = rt
+	}
+}
+
+// WithBaseURL overrides DefaultBaseURL. An empty url keeps the default.
+func WithBaseURL(url string) Option {
+	return func(s *settings) {
+		if url != "" {
+			s.config.URL = strings.TrimRight(url, "/")
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(s *settings) {
+		s.config.UserAgent = ua
+	}
+}
+
+// WithTimeout bounds the duration of every HTTP request, zero means no
+// timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(s *settings) {
+		s.config.Timeout = d
+	}
+}
+
+// WithLogger sets the Logger receiving the diagnostics of the client.
+func WithLogger(l Logger) Option {
+	return func(s *settings) {
+		s.config.Logger = l
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy, a zero RetryPolicy disables
+// retries.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *settings) {
+		s.config.Retry = p
+	}
+}
+
+// WithRateLimit limits the client to rate requests per second with bursts of
+// up to burst requests. A rate of zero disables the client side limit.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(s *settings) {
+		s.config.RateLimit = rate
+		s.config.RateBurst = burst
+	}
+}
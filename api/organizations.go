@@ -0,0 +1,241 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	ReqUser struct {
+		User User `json:"user"`
+	}
+
+	// User is a team member of an organization.
+	User struct {
+		ID             string     `json:"id,omitempty"`
+		OrganizationID string     `json:"organization_id,omitempty"`
+		Email          string     `json:"email,omitempty"`
+		FirstName      string     `json:"first_name,omitempty"`
+		LastName       string     `json:"last_name,omitempty"`
+		CreatedAt      *time.Time `json:"created_at,omitempty"`
+		UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+		// Password is only sent when creating the user; when empty the user
+		// is invited to choose one.
+		Password string `json:"password,omitempty"`
+	}
+
+	// Permissions are the roles of a user on the pages of an organization.
+	Permissions struct {
+		UserID string            `json:"user_id"`
+		Pages  []PagePermissions `json:"pages"`
+	}
+
+	// PagePermissions are the roles of a user on one page.
+	PagePermissions struct {
+		PageID             string `json:"page_id"`
+		PageConfiguration  bool   `json:"page_configuration"`
+		IncidentManager    bool   `json:"incident_manager"`
+		MaintenanceManager bool   `json:"maintenance_manager"`
+	}
+
+	permissionsData struct {
+		Data Permissions `json:"data"`
+	}
+
+	pageRoles struct {
+		PageConfiguration  bool `json:"page_configuration"`
+		IncidentManager    bool `json:"incident_manager"`
+		MaintenanceManager bool `json:"maintenance_manager"`
+	}
+
+	reqPermissions struct {
+		Pages map[string]pageRoles `json:"pages"`
+	}
+)
+
+// GetUsers returns every user of the organization.
+func (c *Client) GetUsers(orgID string) ([]User, error) {
+	return c.GetUsersWithContext(context.Background(), orgID)
+}
+
+func (c *Client) GetUsersWithContext(ctx context.Context, orgID string) ([]User, error) {
+
+	var users []User
+	p := newPager(nil, func(ctx context.Context, o ListOptions) (int, error) {
+		var page []User
+		err := c.do(ctx, request{
+			method:     http.MethodGet,
+			path:       orgPath(orgID, "/users"),
+			query:      o.values(),
+			out:        &page,
+			expect:     http.StatusOK,
+			idempotent: true,
+		})
+		users = append(users, page...)
+		return len(page), err
+	})
+	for p.next(ctx) {
+	}
+
+	return users, p.err
+
+}
+
+// GetUserByEmail returns the user of the organization with the given email.
+func (c *Client) GetUserByEmail(orgID, email string) (User, error) {
+	return c.GetUserByEmailWithContext(context.Background(), orgID, email)
+}
+
+func (c *Client) GetUserByEmailWithContext(ctx context.Context, orgID, email string) (User, error) {
+
+	users, err := c.GetUsersWithContext(ctx, orgID)
+	if err != nil {
+		return User{}, err
+	}
+	for _, u := range users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+
+	return User{}, fmt.Errorf("unable to find user with email %s: %w", email, ErrNotFound)
+}
+
+func (c *Client) CreateUser(orgID string, u User) (User, error) {
+	return c.CreateUserWithContext(context.Background(), orgID, u)
+}
+
+func (c *Client) CreateUserWithContext(ctx context.Context, orgID string, u User) (User, error) {
+
+	user := User{
+		Email:     u.Email,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Password:  u.Password,
+	}
+
+	c.logger().Debug("creating user", "organization_id", orgID)
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   orgPath(orgID, "/users"),
+		body:   ReqUser{User: user},
+		out:    &u,
+		expect: http.StatusCreated,
+	})
+	if err != nil {
+		return u, err
+	}
+	u.Password = ""
+	c.logger().Info("user created", "organization_id", orgID, "user_id", u.ID)
+
+	return u, nil
+
+}
+
+// DeleteUser removes the user from the organization, revoking its access to
+// every page.
+func (c *Client) DeleteUser(orgID, userID string) error {
+	return c.DeleteUserWithContext(context.Background(), orgID, userID)
+}
+
+func (c *Client) DeleteUserWithContext(ctx context.Context, orgID, userID string) error {
+
+	c.logger().Debug("deleting user", "organization_id", orgID, "user_id", userID)
+	err := c.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       orgPath(orgID, "/users/%s", userID),
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+	c.logger().Info("user deleted", "organization_id", orgID, "user_id", userID)
+	return nil
+
+}
+
+// GetPermissions returns the roles of the user on the pages of the
+// organization.
+func (c *Client) GetPermissions(orgID, userID string) (Permissions, error) {
+	return c.GetPermissionsWithContext(context.Background(), orgID, userID)
+}
+
+func (c *Client) GetPermissionsWithContext(ctx context.Context, orgID, userID string) (Permissions, error) {
+
+	var perms permissionsData
+	err := c.do(ctx, request{
+		method:     http.MethodGet,
+		path:       orgPath(orgID, "/permissions/%s", userID),
+		out:        &perms,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return perms.Data, err
+
+}
+
+// UpdatePermissions sets the roles of the user to the ones of perms. Pages
+// missing from perms are left unchanged.
+func (c *Client) UpdatePermissions(orgID string, perms Permissions) (Permissions, error) {
+	return c.UpdatePermissionsWithContext(context.Background(), orgID, perms)
+}
+
+func (c *Client) UpdatePermissionsWithContext(ctx context.Context, orgID string, perms Permissions) (Permissions, error) {
+
+	body := reqPermissions{Pages: make(map[string]pageRoles, len(perms.Pages))}
+	for _, p := range perms.Pages {
+		body.Pages[p.PageID] = pageRoles{
+			PageConfiguration:  p.PageConfiguration,
+			IncidentManager:    p.IncidentManager,
+			MaintenanceManager: p.MaintenanceManager,
+		}
+	}
+
+	c.logger().Debug("updating permissions", "organization_id", orgID, "user_id", perms.UserID, "pages", len(perms.Pages))
+	var updated permissionsData
+	err := c.do(ctx, request{
+		method:     http.MethodPut,
+		path:       orgPath(orgID, "/permissions/%s", perms.UserID),
+		body:       body,
+		out:        &updated,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+	if err != nil {
+		return perms, err
+	}
+	c.logger().Info("permissions updated", "organization_id", orgID, "user_id", perms.UserID)
+
+	return updated.Data, nil
+
+}
+
+// RevokePermissions removes every role of the user on every page of the
+// organization while keeping the user in the organization.
+func (c *Client) RevokePermissions(orgID, userID string) (Permissions, error) {
+	return c.RevokePermissionsWithContext(context.Background(), orgID, userID)
+}
+
+func (c *Client) RevokePermissionsWithContext(ctx context.Context, orgID, userID string) (Permissions, error) {
+
+	perms, err := c.GetPermissionsWithContext(ctx, orgID, userID)
+	if err != nil {
+		return perms, err
+	}
+	perms.UserID = userID
+	for i, p := range perms.Pages {
+		perms.Pages[i] = PagePermissions{PageID: p.PageID}
+	}
+
+	return c.UpdatePermissionsWithContext(ctx, orgID, perms)
+
+}
+
+// orgPath returns the API path of a resource under the organization.
+func orgPath(orgID, format string, a ...interface{}) string {
+
+	return fmt.Sprintf("/v1/organizations/%s", orgID) + fmt.Sprintf(format, a...)
+}
@@ -0,0 +1,522 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type (
+	ReqPageAccessUser struct {
+		PageAccessUser PageAccessUser `json:"page_access_user"`
+	}
+
+	ReqPageAccessGroup struct {
+		PageAccessGroup PageAccessGroup `json:"page_access_group"`
+	}
+
+	// PageAccessUser is a viewer of an audience-specific page.
+	PageAccessUser struct {
+		ID                    string     `json:"id,omitempty"`
+		PageID                string     `json:"page_id,omitempty"`
+		Email                 string     `json:"email,omitempty"`
+		ExternalLogin         string     `json:"external_login,omitempty"`
+		PageAccessGroupIDs    []string   `json:"page_access_group_ids,omitempty"`
+		SubscribeToComponents bool       `json:"subscribe_to_components,omitempty"`
+		CreatedAt             *time.Time `json:"created_at,omitempty"`
+		UpdatedAt             *time.Time `json:"updated_at,omitempty"`
+	}
+
+	// PageAccessGroup grants its users visibility of a set of components and
+	// metrics of an audience-specific page.
+	PageAccessGroup struct {
+		ID                 string     `json:"id,omitempty"`
+		PageID             string     `json:"page_id,omitempty"`
+		Name               string     `json:"name,omitempty"`
+		ExternalIdentifier string     `json:"external_identifier,omitempty"`
+		PageAccessUserIDs  []string   `json:"page_access_user_ids,omitempty"`
+		ComponentIDs       []string   `json:"component_ids,omitempty"`
+		MetricIDs          []string   `json:"metric_ids,omitempty"`
+		CreatedAt          *time.Time `json:"created_at,omitempty"`
+		UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+	}
+
+	accessComponents struct {
+		ComponentIDs []string `json:"component_ids"`
+	}
+
+	accessMetrics struct {
+		MetricIDs []string `json:"metric_ids"`
+	}
+)
+
+// GetPageAccessUsers returns every page access user of the page.
+func (s StatusPage) GetPageAccessUsers() ([]PageAccessUser, error) {
+	return s.GetPageAccessUsersWithContext(context.Background())
+}
+
+func (s StatusPage) GetPageAccessUsersWithContext(ctx context.Context) ([]PageAccessUser, error) {
+
+	var users []PageAccessUser
+	p := newPager(nil, func(ctx context.Context, o ListOptions) (int, error) {
+		var page []PageAccessUser
+		err := s.Client.do(ctx, request{
+			method:     http.MethodGet,
+			path:       s.pagePath("/page_access_users"),
+			query:      o.values(),
+			out:        &page,
+			expect:     http.StatusOK,
+			idempotent: true,
+		})
+		users = append(users, page...)
+		return len(page), err
+	})
+	for p.next(ctx) {
+	}
+
+	return users, p.err
+
+}
+
+func (s StatusPage) GetPageAccessUser(id string) (PageAccessUser, error) {
+	return s.GetPageAccessUserWithContext(context.Background(), id)
+}
+
+func (s StatusPage) GetPageAccessUserWithContext(ctx context.Context, id string) (PageAccessUser, error) {
+
+	var u PageAccessUser
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/page_access_users/%s", id),
+		out:        &u,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return u, err
+
+}
+
+func (s StatusPage) CreatePageAccessUser(u PageAccessUser) (PageAccessUser, error) {
+	return s.CreatePageAccessUserWithContext(context.Background(), u)
+}
+
+func (s StatusPage) CreatePageAccessUserWithContext(ctx context.Context, u PageAccessUser) (PageAccessUser, error) {
+
+	user := PageAccessUser{
+		Email:                 u.Email,
+		ExternalLogin:         u.ExternalLogin,
+		PageAccessGroupIDs:    u.PageAccessGroupIDs,
+		SubscribeToComponents: u.SubscribeToComponents,
+	}
+
+	s.Client.logger().Debug("creating page access user")
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/page_access_users"),
+		body:   ReqPageAccessUser{PageAccessUser: user},
+		out:    &u,
+		expect: http.StatusCreated,
+	})
+	if err != nil {
+		return u, err
+	}
+	s.Client.logger().Info("page access user created", "page_access_user_id", u.ID)
+
+	return u, nil
+
+}
+
+func (s StatusPage) UpdatePageAccessUser(u PageAccessUser) (PageAccessUser, error) {
+	return s.UpdatePageAccessUserWithContext(context.Background(), u)
+}
+
+func (s StatusPage) UpdatePageAccessUserWithContext(ctx context.Context, u PageAccessUser) (PageAccessUser, error) {
+
+	user := PageAccessUser{
+		ExternalLogin:      u.ExternalLogin,
+		PageAccessGroupIDs: u.PageAccessGroupIDs,
+	}
+
+	s.Client.logger().Debug("updating page access user", "page_access_user_id", u.ID)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPatch,
+		path:   s.pagePath("/page_access_users/%s", u.ID),
+		body:   ReqPageAccessUser{PageAccessUser: user},
+		out:    &u,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return u, err
+	}
+	s.Client.logger().Info("page access user updated", "page_access_user_id", u.ID)
+
+	return u, nil
+
+}
+
+func (s StatusPage) DeletePageAccessUser(u PageAccessUser) error {
+	return s.DeletePageAccessUserWithContext(context.Background(), u)
+}
+
+func (s StatusPage) DeletePageAccessUserWithContext(ctx context.Context, u PageAccessUser) error {
+
+	s.Client.logger().Debug("deleting page access user", "page_access_user_id", u.ID)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/page_access_users/%s", u.ID),
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+	s.Client.logger().Info("page access user deleted", "page_access_user_id", u.ID)
+	return nil
+
+}
+
+// GetPageAccessUserComponents returns the components visible to the user.
+func (s StatusPage) GetPageAccessUserComponents(u PageAccessUser) ([]Component, error) {
+	return s.GetPageAccessUserComponentsWithContext(context.Background(), u)
+}
+
+func (s StatusPage) GetPageAccessUserComponentsWithContext(ctx context.Context, u PageAccessUser) ([]Component, error) {
+
+	var components []Component
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/page_access_users/%s/components", u.ID),
+		out:        &components,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return components, err
+
+}
+
+// AddPageAccessUserComponents makes the components with the given ids
+// visible to the user.
+func (s StatusPage) AddPageAccessUserComponents(u PageAccessUser, componentIDs []string) (PageAccessUser, error) {
+	return s.AddPageAccessUserComponentsWithContext(context.Background(), u, componentIDs)
+}
+
+func (s StatusPage) AddPageAccessUserComponentsWithContext(ctx context.Context, u PageAccessUser, componentIDs []string) (PageAccessUser, error) {
+
+	err := s.changeAccess(ctx, http.MethodPatch, s.pagePath("/page_access_users/%s/components", u.ID), accessComponents{componentIDs}, &u)
+	return u, err
+}
+
+// ReplacePageAccessUserComponents makes exactly the components with the
+// given ids visible to the user.
+func (s StatusPage) ReplacePageAccessUserComponents(u PageAccessUser, componentIDs []string) (PageAccessUser, error) {
+	return s.ReplacePageAccessUserComponentsWithContext(context.Background(), u, componentIDs)
+}
+
+func (s StatusPage) ReplacePageAccessUserComponentsWithContext(ctx context.Context, u PageAccessUser, componentIDs []string) (PageAccessUser, error) {
+
+	err := s.changeAccess(ctx, http.MethodPut, s.pagePath("/page_access_users/%s/components", u.ID), accessComponents{componentIDs}, &u)
+	return u, err
+}
+
+// RemovePageAccessUserComponents hides the components with the given ids
+// from the user.
+func (s StatusPage) RemovePageAccessUserComponents(u PageAccessUser, componentIDs []string) (PageAccessUser, error) {
+	return s.RemovePageAccessUserComponentsWithContext(context.Background(), u, componentIDs)
+}
+
+func (s StatusPage) RemovePageAccessUserComponentsWithContext(ctx context.Context, u PageAccessUser, componentIDs []string) (PageAccessUser, error) {
+
+	err := s.changeAccess(ctx, http.MethodDelete, s.pagePath("/page_access_users/%s/components", u.ID), accessComponents{componentIDs}, &u)
+	return u, err
+}
+
+// GetPageAccessUserMetrics returns the metrics visible to the user.
+func (s StatusPage) GetPageAccessUserMetrics(u PageAccessUser) ([]Metric, error) {
+	return s.GetPageAccessUserMetricsWithContext(context.Background(), u)
+}
+
+func (s StatusPage) GetPageAccessUserMetricsWithContext(ctx context.Context, u PageAccessUser) ([]Metric, error) {
+
+	var metrics []Metric
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/page_access_users/%s/metrics", u.ID),
+		out:        &metrics,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return metrics, err
+
+}
+
+// AddPageAccessUserMetrics makes the metrics with the given ids visible to
+// the user.
+func (s StatusPage) AddPageAccessUserMetrics(u PageAccessUser, metricIDs []string) (PageAccessUser, error) {
+	return s.AddPageAccessUserMetricsWithContext(context.Background(), u, metricIDs)
+}
+
+func (s StatusPage) AddPageAccessUserMetricsWithContext(ctx context.Context, u PageAccessUser, metricIDs []string) (PageAccessUser, error) {
+
+	err := s.changeAccess(ctx, http.MethodPatch, s.pagePath("/page_access_users/%s/metrics", u.ID), accessMetrics{metricIDs}, &u)
+	return u, err
+}
+
+// ReplacePageAccessUserMetrics makes exactly the metrics with the given ids
+// visible to the user.
+func (s StatusPage) ReplacePageAccessUserMetrics(u PageAccessUser, metricIDs []string) (PageAccessUser, error) {
+	return s.ReplacePageAccessUserMetricsWithContext(context.Background(), u, metricIDs)
+}
+
+func (s StatusPage) ReplacePageAccessUserMetricsWithContext(ctx context.Context, u PageAccessUser, metricIDs []string) (PageAccessUser, error) {
+
+	err := s.changeAccess(ctx, http.MethodPut, s.pagePath("/page_access_users/%s/metrics", u.ID), accessMetrics{metricIDs}, &u)
+	return u, err
+}
+
+// RemovePageAccessUserMetrics hides the metrics with the given ids from the
+// user.
+func (s StatusPage) RemovePageAccessUserMetrics(u PageAccessUser, metricIDs []string) (PageAccessUser, error) {
+	return s.RemovePageAccessUserMetricsWithContext(context.Background(), u, metricIDs)
+}
+
+func (s StatusPage) RemovePageAccessUserMetricsWithContext(ctx context.Context, u PageAccessUser, metricIDs []string) (PageAccessUser, error) {
+
+	err := s.changeAccess(ctx, http.MethodDelete, s.pagePath("/page_access_users/%s/metrics", u.ID), accessMetrics{metricIDs}, &u)
+	return u, err
+}
+
+// GetPageAccessGroups returns every page access group of the page.
+func (s StatusPage) GetPageAccessGroups() ([]PageAccessGroup, error) {
+	return s.GetPageAccessGroupsWithContext(context.Background())
+}
+
+func (s StatusPage) GetPageAccessGroupsWithContext(ctx context.Context) ([]PageAccessGroup, error) {
+
+	var groups []PageAccessGroup
+	p := newPager(nil, func(ctx context.Context, o ListOptions) (int, error) {
+		var page []PageAccessGroup
+		err := s.Client.do(ctx, request{
+			method:     http.MethodGet,
+			path:       s.pagePath("/page_access_groups"),
+			query:      o.values(),
+			out:        &page,
+			expect:     http.StatusOK,
+			idempotent: true,
+		})
+		groups = append(groups, page...)
+		return len(page), err
+	})
+	for p.next(ctx) {
+	}
+
+	return groups, p.err
+
+}
+
+func (s StatusPage) GetPageAccessGroup(id string) (PageAccessGroup, error) {
+	return s.GetPageAccessGroupWithContext(context.Background(), id)
+}
+
+func (s StatusPage) GetPageAccessGroupWithContext(ctx context.Context, id string) (PageAccessGroup, error) {
+
+	var g PageAccessGroup
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/page_access_groups/%s", id),
+		out:        &g,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return g, err
+
+}
+
+func (s StatusPage) CreatePageAccessGroup(g PageAccessGroup) (PageAccessGroup, error) {
+	return s.CreatePageAccessGroupWithContext(context.Background(), g)
+}
+
+func (s StatusPage) CreatePageAccessGroupWithContext(ctx context.Context, g PageAccessGroup) (PageAccessGroup, error) {
+
+	s.Client.logger().Debug("creating page access group", "name", g.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/page_access_groups"),
+		body:   ReqPageAccessGroup{PageAccessGroup: pageAccessGroupPayload(g)},
+		out:    &g,
+		expect: http.StatusCreated,
+	})
+	if err != nil {
+		return g, err
+	}
+	s.Client.logger().Info("page access group created", "page_access_group_id", g.ID, "name", g.Name)
+
+	return g, nil
+
+}
+
+func (s StatusPage) UpdatePageAccessGroup(g PageAccessGroup) (PageAccessGroup, error) {
+	return s.UpdatePageAccessGroupWithContext(context.Background(), g)
+}
+
+func (s StatusPage) UpdatePageAccessGroupWithContext(ctx context.Context, g PageAccessGroup) (PageAccessGroup, error) {
+
+	s.Client.logger().Debug("updating page access group", "page_access_group_id", g.ID, "name", g.Name)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPatch,
+		path:   s.pagePath("/page_access_groups/%s", g.ID),
+		body:   ReqPageAccessGroup{PageAccessGroup: pageAccessGroupPayload(g)},
+		out:    &g,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return g, err
+	}
+	s.Client.logger().Info("page access group updated", "page_access_group_id", g.ID, "name", g.Name)
+
+	return g, nil
+
+}
+
+func (s StatusPage) DeletePageAccessGroup(g PageAccessGroup) error {
+	return s.DeletePageAccessGroupWithContext(context.Background(), g)
+}
+
+func (s StatusPage) DeletePageAccessGroupWithContext(ctx context.Context, g PageAccessGroup) error {
+
+	s.Client.logger().Debug("deleting page access group", "page_access_group_id", g.ID, "name", g.Name)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/page_access_groups/%s", g.ID),
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+	s.Client.logger().Info("page access group deleted", "page_access_group_id", g.ID, "name", g.Name)
+	return nil
+
+}
+
+// GetPageAccessGroupComponents returns the components visible to the group.
+func (s StatusPage) GetPageAccessGroupComponents(g PageAccessGroup) ([]Component, error) {
+	return s.GetPageAccessGroupComponentsWithContext(context.Background(), g)
+}
+
+func (s StatusPage) GetPageAccessGroupComponentsWithContext(ctx context.Context, g PageAccessGroup) ([]Component, error) {
+
+	var components []Component
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/page_access_groups/%s/components", g.ID),
+		out:        &components,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return components, err
+
+}
+
+// AddPageAccessGroupComponents makes the components with the given ids
+// visible to the group.
+func (s StatusPage) AddPageAccessGroupComponents(g PageAccessGroup, componentIDs []string) (PageAccessGroup, error) {
+	return s.AddPageAccessGroupComponentsWithContext(context.Background(), g, componentIDs)
+}
+
+func (s StatusPage) AddPageAccessGroupComponentsWithContext(ctx context.Context, g PageAccessGroup, componentIDs []string) (PageAccessGroup, error) {
+
+	err := s.changeAccess(ctx, http.MethodPatch, s.pagePath("/page_access_groups/%s/components", g.ID), accessComponents{componentIDs}, &g)
+	return g, err
+}
+
+// ReplacePageAccessGroupComponents makes exactly the components with the
+// given ids visible to the group.
+func (s StatusPage) ReplacePageAccessGroupComponents(g PageAccessGroup, componentIDs []string) (PageAccessGroup, error) {
+	return s.ReplacePageAccessGroupComponentsWithContext(context.Background(), g, componentIDs)
+}
+
+func (s StatusPage) ReplacePageAccessGroupComponentsWithContext(ctx context.Context, g PageAccessGroup, componentIDs []string) (PageAccessGroup, error) {
+
+	err := s.changeAccess(ctx, http.MethodPut, s.pagePath("/page_access_groups/%s/components", g.ID), accessComponents{componentIDs}, &g)
+	return g, err
+}
+
+// RemovePageAccessGroupComponents hides the components with the given ids
+// from the group.
+func (s StatusPage) RemovePageAccessGroupComponents(g PageAccessGroup, componentIDs []string) (PageAccessGroup, error) {
+	return s.RemovePageAccessGroupComponentsWithContext(context.Background(), g, componentIDs)
+}
+
+func (s StatusPage) RemovePageAccessGroupComponentsWithContext(ctx context.Context, g PageAccessGroup, componentIDs []string) (PageAccessGroup, error) {
+
+	err := s.changeAccess(ctx, http.MethodDelete, s.pagePath("/page_access_groups/%s/components", g.ID), accessComponents{componentIDs}, &g)
+	return g, err
+}
+
+// AddPageAccessGroupMetrics makes the metrics with the given ids visible to
+// the group.
+func (s StatusPage) AddPageAccessGroupMetrics(g PageAccessGroup, metricIDs []string) (PageAccessGroup, error) {
+	return s.AddPageAccessGroupMetricsWithContext(context.Background(), g, metricIDs)
+}
+
+func (s StatusPage) AddPageAccessGroupMetricsWithContext(ctx context.Context, g PageAccessGroup, metricIDs []string) (PageAccessGroup, error) {
+
+	err := s.changeAccess(ctx, http.MethodPatch, s.pagePath("/page_access_groups/%s/metrics", g.ID), accessMetrics{metricIDs}, &g)
+	return g, err
+}
+
+// ReplacePageAccessGroupMetrics makes exactly the metrics with the given ids
+// visible to the group.
+func (s StatusPage) ReplacePageAccessGroupMetrics(g PageAccessGroup, metricIDs []string) (PageAccessGroup, error) {
+	return s.ReplacePageAccessGroupMetricsWithContext(context.Background(), g, metricIDs)
+}
+
+func (s StatusPage) ReplacePageAccessGroupMetricsWithContext(ctx context.Context, g PageAccessGroup, metricIDs []string) (PageAccessGroup, error) {
+
+	err := s.changeAccess(ctx, http.MethodPut, s.pagePath("/page_access_groups/%s/metrics", g.ID), accessMetrics{metricIDs}, &g)
+	return g, err
+}
+
+// RemovePageAccessGroupMetrics hides the metrics with the given ids from the
+// group.
+func (s StatusPage) RemovePageAccessGroupMetrics(g PageAccessGroup, metricIDs []string) (PageAccessGroup, error) {
+	return s.RemovePageAccessGroupMetricsWithContext(context.Background(), g, metricIDs)
+}
+
+func (s StatusPage) RemovePageAccessGroupMetricsWithContext(ctx context.Context, g PageAccessGroup, metricIDs []string) (PageAccessGroup, error) {
+
+	err := s.changeAccess(ctx, http.MethodDelete, s.pagePath("/page_access_groups/%s/metrics", g.ID), accessMetrics{metricIDs}, &g)
+	return g, err
+}
+
+// changeAccess adds (PATCH), replaces (PUT) or removes (DELETE) the
+// components or metrics visible to a page access user or group, decoding the
+// updated user or group into out. All three are set operations, so they are
+// safe to retry.
+func (s StatusPage) changeAccess(ctx context.Context, method, path string, ids interface{}, out interface{}) error {
+
+	s.Client.logger().Debug("changing page access", "method", method, "path", path)
+	return s.Client.do(ctx, request{
+		method:     method,
+		path:       path,
+		body:       ids,
+		out:        out,
+		idempotent: true,
+	})
+}
+
+// pageAccessGroupPayload keeps the fields of g accepted when creating or
+// updating a group.
+func pageAccessGroupPayload(g PageAccessGroup) PageAccessGroup {
+
+	return PageAccessGroup{
+		Name:               g.Name,
+		ExternalIdentifier: g.ExternalIdentifier,
+		PageAccessUserIDs:  g.PageAccessUserIDs,
+		ComponentIDs:       g.ComponentIDs,
+		MetricIDs:          g.MetricIDs,
+	}
+}
@@ -0,0 +1,609 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidPageSpec is returned when a PageSpec cannot be planned because
+// it is incomplete or ambiguous.
+var ErrInvalidPageSpec = errors.New("invalid page spec")
+
+type (
+	// PageSpec declares the component groups and components a page should
+	// have, as read by ParsePageSpec from JSON such as
+	//
+	//	{
+	//	  "groups": [
+	//	    {"name": "API", "components": [{"name": "REST"}, {"name": "GraphQL"}]}
+	//	  ],
+	//	  "components": [{"name": "Website", "showcase": true}]
+	//	}
+	//
+	// Groups and components are identified by name, a component being
+	// renamed is deleted and created again. The order of the components of
+	// a group is their position in the group. The API cannot order groups
+	// and ungrouped components, so their order is not enforced.
+	PageSpec struct {
+		Groups []GroupSpec `json:"groups"`
+		// Components are the components outside of any group.
+		Components []ComponentSpec `json:"components"`
+	}
+
+	// GroupSpec declares a component group and its components, a group
+	// having at least one component.
+	GroupSpec struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Components  []ComponentSpec `json:"components"`
+	}
+
+	// ComponentSpec declares a component. Showcase shows its uptime on the
+	// page and OnlyShowIfDegraded hides it while it is operational.
+	ComponentSpec struct {
+		Name               string `json:"name"`
+		Description        string `json:"description"`
+		Showcase           bool   `json:"showcase"`
+		OnlyShowIfDegraded bool   `json:"only_show_if_degraded"`
+	}
+
+	PlanAction string
+	PlanKind   string
+
+	// PlanChange is a single call made when applying a Plan.
+	PlanChange struct {
+		Action PlanAction
+		Kind   PlanKind
+		Name   string
+		// Group is the group of a component, empty when it has none. For a
+		// deleted component it is the group it is in.
+		Group string
+		// Diff describes the fields set by a create or changed by an
+		// update, e.g. `description: "old" -> "new"`.
+		Diff []string
+
+		id        string
+		component ComponentSpec
+		group     GroupSpec
+	}
+
+	// Plan is the list of changes bringing a page to its PageSpec, in the
+	// order ApplyPlan makes them: components are created and updated before
+	// the groups holding them, and deletions come last.
+	Plan struct {
+		Changes []PlanChange
+
+		// ids of the components that already exist, by specKey
+		ids map[string]string
+	}
+)
+
+const (
+	PlanCreate PlanAction = "create"
+	PlanUpdate PlanAction = "update"
+	PlanDelete PlanAction = "delete"
+
+	PlanKindComponent PlanKind = "component"
+	PlanKindGroup     PlanKind = "group"
+)
+
+func (a PlanAction) String() string {
+
+	return string(a)
+}
+
+func (k PlanKind) String() string {
+
+	return string(k)
+}
+
+// ParsePageSpec reads a JSON PageSpec from r and validates it. Unknown fields
+// are rejected so typos do not go unnoticed.
+func ParsePageSpec(r io.Reader) (PageSpec, error) {
+
+	var spec PageSpec
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return spec, fmt.Errorf("unable to parse page spec: %w", err)
+	}
+
+	return spec, spec.Validate()
+}
+
+// Validate checks that every group and component is named, that group names
+// are unique, that component names are unique within their group and that
+// no group is empty.
+func (spec PageSpec) Validate() error {
+
+	seen := map[string]bool{}
+	check := func(group string, c ComponentSpec) error {
+		if strings.TrimSpace(c.Name) == "" {
+			return fmt.Errorf("component without a name in group %q: %w", group, ErrInvalidPageSpec)
+		}
+		k := specKey(group, c.Name)
+		if seen[k] {
+			return fmt.Errorf("duplicate component %q in group %q: %w", c.Name, group, ErrInvalidPageSpec)
+		}
+		seen[k] = true
+		return nil
+	}
+
+	for _, c := range spec.Components {
+		if err := check("", c); err != nil {
+			return err
+		}
+	}
+	groups := map[string]bool{}
+	for _, g := range spec.Groups {
+		switch {
+		case strings.TrimSpace(g.Name) == "":
+			return fmt.Errorf("group without a name: %w", ErrInvalidPageSpec)
+		case groups[g.Name]:
+			return fmt.Errorf("duplicate group %q: %w", g.Name, ErrInvalidPageSpec)
+		case len(g.Components) == 0:
+			return fmt.Errorf("group %q has no components: %w", g.Name, ErrInvalidPageSpec)
+		}
+		groups[g.Name] = true
+		for _, c := range g.Components {
+			if err := check(g.Name, c); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetPageSpec returns the spec of the current groups and components of the
+// page, a starting point for managing an existing page declaratively.
+func (s StatusPage) GetPageSpec() (PageSpec, error) {
+	return s.GetPageSpecWithContext(context.Background())
+}
+
+func (s StatusPage) GetPageSpecWithContext(ctx context.Context) (PageSpec, error) {
+
+	var spec PageSpec
+
+	groups, err := s.GetComponentGroupsWithContext(ctx)
+	if err != nil {
+		return spec, err
+	}
+	components, err := s.GetComponentsWithContext(ctx)
+	if err != nil {
+		return spec, err
+	}
+
+	byID := map[string]Component{}
+	for _, c := range components {
+		if c.Group {
+			continue
+		}
+		byID[c.ID] = c
+		if c.GroupID == "" {
+			spec.Components = append(spec.Components, componentSpec(c))
+		}
+	}
+	for _, g := range groups {
+		gs := GroupSpec{Name: g.Name, Description: g.Description}
+		for _, id := range g.Components {
+			if c, ok := byID[id]; ok {
+				gs.Components = append(gs.Components, componentSpec(c))
+			}
+		}
+		spec.Groups = append(spec.Groups, gs)
+	}
+
+	return spec, nil
+
+}
+
+// PlanPageSpec compares spec with the groups and components of the page and
+// returns the changes needed to match it, without making them. Groups and
+// components missing from spec are deleted.
+func (s StatusPage) PlanPageSpec(spec PageSpec) (Plan, error) {
+	return s.PlanPageSpecWithContext(context.Background(), spec)
+}
+
+func (s StatusPage) PlanPageSpecWithContext(ctx context.Context, spec PageSpec) (Plan, error) {
+
+	if err := spec.Validate(); err != nil {
+		return Plan{}, err
+	}
+	groups, err := s.GetComponentGroupsWithContext(ctx)
+	if err != nil {
+		return Plan{}, err
+	}
+	components, err := s.GetComponentsWithContext(ctx)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return planPageSpec(spec, groups, components)
+
+}
+
+func planPageSpec(spec PageSpec, groups []ComponentGroup, components []Component) (Plan, error) {
+
+	plan := Plan{ids: map[string]string{}}
+
+	groupByName := map[string]ComponentGroup{}
+	groupName := map[string]string{}
+	for _, g := range groups {
+		if _, ok := groupByName[g.Name]; ok {
+			return plan, fmt.Errorf("page has several groups named %q: %w", g.Name, ErrInvalidPageSpec)
+		}
+		groupByName[g.Name] = g
+		groupName[g.ID] = g.Name
+	}
+
+	var existing []Component
+	byKey := map[string]int{}
+	componentName := map[string]string{}
+	for _, c := range components {
+		if c.Group {
+			continue
+		}
+		k := specKey(groupName[c.GroupID], c.Name)
+		if _, ok := byKey[k]; ok {
+			return plan, fmt.Errorf("page has several components named %q in group %q: %w",
+				c.Name, groupName[c.GroupID], ErrInvalidPageSpec)
+		}
+		byKey[k] = len(existing)
+		existing = append(existing, c)
+		componentName[c.ID] = c.Name
+	}
+
+	type wanted struct {
+		group string
+		spec  ComponentSpec
+		match int
+	}
+	var wants []wanted
+	for _, c := range spec.Components {
+		wants = append(wants, wanted{spec: c, match: -1})
+	}
+	for _, g := range spec.Groups {
+		for _, c := range g.Components {
+			wants = append(wants, wanted{group: g.Name, spec: c, match: -1})
+		}
+	}
+
+	// match components in the same group first, then components moved to
+	// another group when their name is unambiguous
+	used := map[int]bool{}
+	for i, w := range wants {
+		if j, ok := byKey[specKey(w.group, w.spec.Name)]; ok {
+			wants[i].match = j
+			used[j] = true
+		}
+	}
+	for i, w := range wants {
+		if w.match >= 0 {
+			continue
+		}
+		match := -1
+		for j, c := range existing {
+			if used[j] || c.Name != w.spec.Name {
+				continue
+			}
+			if match >= 0 {
+				match = -1
+				break
+			}
+			match = j
+		}
+		if match >= 0 {
+			wants[i].match = match
+			used[match] = true
+		}
+	}
+
+	for _, w := range wants {
+		if w.match >= 0 {
+			continue
+		}
+		plan.Changes = append(plan.Changes, PlanChange{
+			Action:    PlanCreate,
+			Kind:      PlanKindComponent,
+			Name:      w.spec.Name,
+			Group:     w.group,
+			Diff:      componentDiff(ComponentSpec{}, w.spec),
+			component: w.spec,
+		})
+	}
+	for _, w := range wants {
+		if w.match < 0 {
+			continue
+		}
+		c := existing[w.match]
+		plan.ids[specKey(w.group, w.spec.Name)] = c.ID
+		if c.GroupID != "" && w.group == "" {
+			if _, ok := groupByName[groupName[c.GroupID]]; ok && !specHasGroup(spec, groupName[c.GroupID]) {
+				return plan, fmt.Errorf("component %q cannot leave group %q while the group is deleted, ungroup it first: %w",
+					c.Name, groupName[c.GroupID], ErrInvalidPageSpec)
+			}
+		}
+		if diff := componentDiff(componentSpec(c), w.spec); len(diff) > 0 {
+			plan.Changes = append(plan.Changes, PlanChange{
+				Action:    PlanUpdate,
+				Kind:      PlanKindComponent,
+				Name:      w.spec.Name,
+				Group:     w.group,
+				Diff:      diff,
+				id:        c.ID,
+				component: w.spec,
+			})
+		}
+	}
+
+	for _, g := range spec.Groups {
+		if _, ok := groupByName[g.Name]; ok {
+			continue
+		}
+		var diff []string
+		if g.Description != "" {
+			diff = append(diff, fmt.Sprintf("description: %q", g.Description))
+		}
+		diff = append(diff, "components: "+quoteNames(specNames(g.Components)))
+		plan.Changes = append(plan.Changes, PlanChange{
+			Action: PlanCreate,
+			Kind:   PlanKindGroup,
+			Name:   g.Name,
+			Diff:   diff,
+			group:  g,
+		})
+	}
+	for _, g := range spec.Groups {
+		current, ok := groupByName[g.Name]
+		if !ok {
+			continue
+		}
+		var diff []string
+		if current.Description != g.Description {
+			diff = append(diff, fmt.Sprintf("description: %q -> %q", current.Description, g.Description))
+		}
+		same := len(current.Components) == len(g.Components)
+		for i, c := range g.Components {
+			if !same {
+				break
+			}
+			same = plan.ids[specKey(g.Name, c.Name)] == current.Components[i]
+		}
+		if !same {
+			var names []string
+			for _, id := range current.Components {
+				names = append(names, componentName[id])
+			}
+			diff = append(diff, fmt.Sprintf("components: %s -> %s", quoteNames(names), quoteNames(specNames(g.Components))))
+		}
+		if len(diff) > 0 {
+			plan.Changes = append(plan.Changes, PlanChange{
+				Action: PlanUpdate,
+				Kind:   PlanKindGroup,
+				Name:   g.Name,
+				Diff:   diff,
+				id:     current.ID,
+				group:  g,
+			})
+		}
+	}
+
+	for j, c := range existing {
+		if used[j] {
+			continue
+		}
+		plan.Changes = append(plan.Changes, PlanChange{
+			Action: PlanDelete,
+			Kind:   PlanKindComponent,
+			Name:   c.Name,
+			Group:  groupName[c.GroupID],
+			id:     c.ID,
+		})
+	}
+	for _, g := range groups {
+		if specHasGroup(spec, g.Name) {
+			continue
+		}
+		plan.Changes = append(plan.Changes, PlanChange{
+			Action: PlanDelete,
+			Kind:   PlanKindGroup,
+			Name:   g.Name,
+			id:     g.ID,
+		})
+	}
+
+	return plan, nil
+}
+
+// Empty reports whether the page already matches its spec.
+func (p Plan) Empty() bool {
+
+	return len(p.Changes) == 0
+}
+
+// String formats the plan for review, one change per line followed by its
+// diff and a summary line.
+func (p Plan) String() string {
+
+	if p.Empty() {
+		return "No changes.\n"
+	}
+
+	var (
+		b      strings.Builder
+		counts = map[PlanAction]int{}
+	)
+	for _, c := range p.Changes {
+		b.WriteString(c.String())
+		b.WriteString("\n")
+		counts[c.Action]++
+	}
+	fmt.Fprintf(&b, "Plan: %d to create, %d to update, %d to delete.\n",
+		counts[PlanCreate], counts[PlanUpdate], counts[PlanDelete])
+
+	return b.String()
+}
+
+func (c PlanChange) String() string {
+
+	symbol := map[PlanAction]string{PlanCreate: "+", PlanUpdate: "~", PlanDelete: "-"}[c.Action]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s %q", symbol, c.Action, c.Kind, c.Name)
+	if c.Group != "" {
+		fmt.Fprintf(&b, " in group %q", c.Group)
+	}
+	for _, d := range c.Diff {
+		fmt.Fprintf(&b, "\n    %s", d)
+	}
+
+	return b.String()
+}
+
+// ApplyPlan makes the changes of p in order and stops at the first error.
+// Applying a plan again after a failure is not safe, plan again instead.
+func (s StatusPage) ApplyPlan(p Plan) error {
+	return s.ApplyPlanWithContext(context.Background(), p)
+}
+
+func (s StatusPage) ApplyPlanWithContext(ctx context.Context, p Plan) error {
+
+	ids := make(map[string]string, len(p.ids))
+	for k, id := range p.ids {
+		ids[k] = id
+	}
+
+	for _, c := range p.Changes {
+		if err := s.applyChange(ctx, c, ids); err != nil {
+			return fmt.Errorf("unable to %s %s %q: %w", c.Action, c.Kind, c.Name, err)
+		}
+	}
+	s.Client.logger().Info("page spec applied", "page_id", s.Page.ID, "changes", len(p.Changes))
+
+	return nil
+
+}
+
+func (s StatusPage) applyChange(ctx context.Context, c PlanChange, ids map[string]string) error {
+
+	switch {
+	case c.Kind == PlanKindComponent && c.Action == PlanCreate:
+		var created Component
+		if err := s.createComponent(ctx, newComponentPayload(specComponent(c.component)), &created); err != nil {
+			return err
+		}
+		ids[specKey(c.Group, c.Name)] = created.ID
+		return nil
+
+	case c.Kind == PlanKindComponent && c.Action == PlanUpdate:
+		return s.updateComponent(ctx, c.id, newComponentPayload(specComponent(c.component)), &Component{})
+
+	case c.Kind == PlanKindComponent && c.Action == PlanDelete:
+		err := s.DeleteComponentWithContext(ctx, Component{ID: c.id, Name: c.Name})
+		if IsNotFound(err) {
+			return nil
+		}
+		return err
+
+	case c.Kind == PlanKindGroup && c.Action == PlanDelete:
+		// the group may already be gone with its last component
+		err := s.DeleteComponentGroupsWithContext(ctx, ComponentGroup{ID: c.id, Name: c.Name})
+		if IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	g := ComponentGroup{Name: c.group.Name, Description: c.group.Description}
+	for _, comp := range c.group.Components {
+		id, ok := ids[specKey(c.group.Name, comp.Name)]
+		if !ok {
+			return fmt.Errorf("component %q was not created", comp.Name)
+		}
+		g.Components = append(g.Components, id)
+	}
+
+	if c.Action == PlanCreate {
+		return s.createComponentGroup(ctx, newComponentGroupPayload(g), &ComponentGroup{})
+	}
+	return s.updateComponentGroup(ctx, c.id, newComponentGroupPayload(g), &ComponentGroup{})
+
+}
+
+func componentSpec(c Component) ComponentSpec {
+
+	return ComponentSpec{
+		Name:               c.Name,
+		Description:        c.Description,
+		Showcase:           c.Showcase,
+		OnlyShowIfDegraded: c.OnlyShowIfDegraded,
+	}
+}
+
+// specComponent returns the component c declares.
+func specComponent(c ComponentSpec) Component {
+
+	return Component{
+		Name:               c.Name,
+		Description:        c.Description,
+		Showcase:           c.Showcase,
+		OnlyShowIfDegraded: c.OnlyShowIfDegraded,
+	}
+}
+
+func componentDiff(from, to ComponentSpec) []string {
+
+	var diff []string
+	if from.Description != to.Description {
+		diff = append(diff, fmt.Sprintf("description: %q -> %q", from.Description, to.Description))
+	}
+	if from.Showcase != to.Showcase {
+		diff = append(diff, fmt.Sprintf("showcase: %t -> %t", from.Showcase, to.Showcase))
+	}
+	if from.OnlyShowIfDegraded != to.OnlyShowIfDegraded {
+		diff = append(diff, fmt.Sprintf("only_show_if_degraded: %t -> %t", from.OnlyShowIfDegraded, to.OnlyShowIfDegraded))
+	}
+	return diff
+}
+
+func specHasGroup(spec PageSpec, name string) bool {
+
+	for _, g := range spec.Groups {
+		if g.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func specNames(components []ComponentSpec) []string {
+
+	names := make([]string, 0, len(components))
+	for _, c := range components {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func quoteNames(names []string) string {
+
+	if len(names) == 0 {
+		return "none"
+	}
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// specKey identifies a component by the name of its group and its own name.
+func specKey(group, name string) string {
+
+	return group + "\x00" + name
+}
@@ -0,0 +1,492 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePage is an in-memory page serving the component and component group
+// endpoints used by the reconciler. Like the API, it leaves a group
+// description unchanged when an update does not send one.
+type fakePage struct {
+	mu         sync.Mutex
+	seq        int
+	components []Component
+	groups     []ComponentGroup
+}
+
+func (f *fakePage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/pages/p1")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	id := ""
+	if len(parts) > 1 {
+		id = parts[1]
+	}
+
+	var (
+		out    interface{}
+		status = http.StatusOK
+		err    error
+	)
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("page") != "1":
+		out = []struct{}{}
+	case r.Method == http.MethodGet && path == "/components":
+		list := append([]Component{}, f.components...)
+		for _, g := range f.groups {
+			list = append(list, Component{ID: g.ID, Name: g.Name, Group: true})
+		}
+		out = list
+	case r.Method == http.MethodGet && path == "/component-groups":
+		out = f.groups
+	case r.Method == http.MethodPost && path == "/components":
+		var body reqComponentPayload
+		err = json.NewDecoder(r.Body).Decode(&body)
+		f.seq++
+		c := Component{ID: fmt.Sprintf("c%d", f.seq)}
+		setComponent(&c, body.Component)
+		f.components = append(f.components, c)
+		out, status = c, http.StatusCreated
+	case r.Method == http.MethodPut && parts[0] == "components":
+		var body reqComponentPayload
+		err = json.NewDecoder(r.Body).Decode(&body)
+		i := f.component(id)
+		if i < 0 {
+			status = http.StatusNotFound
+			break
+		}
+		setComponent(&f.components[i], body.Component)
+		out = f.components[i]
+	case r.Method == http.MethodDelete && parts[0] == "components":
+		i := f.component(id)
+		if i < 0 {
+			status = http.StatusNotFound
+			break
+		}
+		f.components = append(f.components[:i], f.components[i+1:]...)
+		for gi := range f.groups {
+			f.groups[gi].Components = without(f.groups[gi].Components, id)
+		}
+	case (r.Method == http.MethodPost || r.Method == http.MethodPut) && parts[0] == "component-groups":
+		var body struct {
+			Description    *string `json:"description"`
+			ComponentGroup struct {
+				Name       string   `json:"name"`
+				Components []string `json:"components"`
+			} `json:"component_group"`
+		}
+		err = json.NewDecoder(r.Body).Decode(&body)
+		gi := len(f.groups)
+		if r.Method == http.MethodPost {
+			f.seq++
+			f.groups = append(f.groups, ComponentGroup{ID: fmt.Sprintf("g%d", f.seq)})
+			status = http.StatusCreated
+		} else if gi = f.group(id); gi < 0 {
+			status = http.StatusNotFound
+			break
+		}
+		g := &f.groups[gi]
+		g.Name = body.ComponentGroup.Name
+		if body.Description != nil {
+			g.Description = *body.Description
+		}
+		for _, cid := range g.Components {
+			f.components[f.component(cid)].GroupID = ""
+		}
+		for _, cid := range body.ComponentGroup.Components {
+			for oi := range f.groups {
+				f.groups[oi].Components = without(f.groups[oi].Components, cid)
+			}
+			f.components[f.component(cid)].GroupID = g.ID
+		}
+		g.Components = body.ComponentGroup.Components
+		out = *g
+	case r.Method == http.MethodDelete && parts[0] == "component-groups":
+		gi := f.group(id)
+		if gi < 0 {
+			status = http.StatusNotFound
+			break
+		}
+		for _, cid := range f.groups[gi].Components {
+			f.components[f.component(cid)].GroupID = ""
+		}
+		f.groups = append(f.groups[:gi], f.groups[gi+1:]...)
+	default:
+		status = http.StatusNotImplemented
+	}
+
+	if err != nil {
+		status = http.StatusBadRequest
+	}
+	w.WriteHeader(status)
+	if out != nil && status < 300 {
+		json.NewEncoder(w).Encode(out)
+	}
+}
+
+func (f *fakePage) component(id string) int {
+
+	for i, c := range f.components {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *fakePage) group(id string) int {
+
+	for i, g := range f.groups {
+		if g.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func setComponent(c *Component, p componentPayload) {
+
+	c.Name = p.Name
+	c.Description = p.Description
+	c.Showcase = p.Showcase
+	c.OnlyShowIfDegraded = p.OnlyShowIfDegraded
+}
+
+func without(ids []string, id string) []string {
+
+	var out []string
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// newFakePage returns a fake page holding the groups and components of spec.
+func newFakePage(spec PageSpec) *fakePage {
+
+	f := &fakePage{}
+	add := func(c ComponentSpec, groupID string) string {
+		f.seq++
+		comp := Component{ID: fmt.Sprintf("c%d", f.seq), GroupID: groupID}
+		setComponent(&comp, newComponentPayload(specComponent(c)))
+		f.components = append(f.components, comp)
+		return comp.ID
+	}
+	for _, c := range spec.Components {
+		add(c, "")
+	}
+	for _, g := range spec.Groups {
+		f.seq++
+		group := ComponentGroup{ID: fmt.Sprintf("g%d", f.seq), Name: g.Name, Description: g.Description}
+		for _, c := range g.Components {
+			group.Components = append(group.Components, add(c, group.ID))
+		}
+		f.groups = append(f.groups, group)
+	}
+	return f
+}
+
+func TestPageSpecApplyConverges(t *testing.T) {
+
+	api := GroupSpec{Name: "API", Description: "public api", Components: []ComponentSpec{{Name: "REST"}, {Name: "GraphQL"}}}
+
+	tests := []struct {
+		name    string
+		current PageSpec
+		spec    PageSpec
+	}{
+		{
+			name: "empty page",
+			spec: PageSpec{
+				Groups:     []GroupSpec{api},
+				Components: []ComponentSpec{{Name: "Website", Showcase: true}},
+			},
+		},
+		{
+			name:    "clear group description",
+			current: PageSpec{Groups: []GroupSpec{api}},
+			spec:    PageSpec{Groups: []GroupSpec{{Name: "API", Components: api.Components}}},
+		},
+		{
+			name:    "turn showcase off and clear description",
+			current: PageSpec{Components: []ComponentSpec{{Name: "Website", Description: "www", Showcase: true, OnlyShowIfDegraded: true}}},
+			spec:    PageSpec{Components: []ComponentSpec{{Name: "Website"}}},
+		},
+		{
+			name:    "reorder group",
+			current: PageSpec{Groups: []GroupSpec{api}},
+			spec:    PageSpec{Groups: []GroupSpec{{Name: "API", Description: "public api", Components: []ComponentSpec{{Name: "GraphQL"}, {Name: "REST"}}}}},
+		},
+		{
+			name: "move between groups",
+			current: PageSpec{Groups: []GroupSpec{
+				api,
+				{Name: "Web", Components: []ComponentSpec{{Name: "Website"}}},
+			}},
+			spec: PageSpec{Groups: []GroupSpec{
+				{Name: "API", Description: "public api", Components: []ComponentSpec{{Name: "REST"}}},
+				{Name: "Web", Components: []ComponentSpec{{Name: "Website"}, {Name: "GraphQL"}}},
+			}},
+		},
+		{
+			name:    "move into a new group and out of a kept one",
+			current: PageSpec{Groups: []GroupSpec{api}, Components: []ComponentSpec{{Name: "Website"}}},
+			spec: PageSpec{
+				Groups: []GroupSpec{
+					{Name: "API", Description: "public api", Components: []ComponentSpec{{Name: "REST"}}},
+					{Name: "Web", Components: []ComponentSpec{{Name: "Website"}}},
+				},
+				Components: []ComponentSpec{{Name: "GraphQL"}},
+			},
+		},
+		{
+			name:    "delete group and components",
+			current: PageSpec{Groups: []GroupSpec{api}, Components: []ComponentSpec{{Name: "Website"}}},
+			spec:    PageSpec{Components: []ComponentSpec{{Name: "Website"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(newFakePage(tt.current))
+			defer srv.Close()
+			s := newTestClient(srv).Page("p1")
+
+			plan, err := s.PlanPageSpec(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.Empty() {
+				t.Fatal("first plan is empty")
+			}
+			if err := s.ApplyPlan(plan); err != nil {
+				t.Fatal(err)
+			}
+
+			again, err := s.PlanPageSpec(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !again.Empty() {
+				t.Errorf("plan after apply is not empty:\n%s", again)
+			}
+			got, err := s.GetPageSpec()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.spec) {
+				t.Errorf("page is %+v, want %+v", got, tt.spec)
+			}
+		})
+	}
+}
+
+func TestPlanPageSpec(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		groups     []ComponentGroup
+		components []Component
+		spec       PageSpec
+		changes    []string
+		err        string
+	}{
+		{
+			name: "up to date",
+			groups: []ComponentGroup{
+				{ID: "g1", Name: "API", Components: []string{"c1"}},
+			},
+			components: []Component{
+				{ID: "g1", Name: "API", Group: true},
+				{ID: "c1", Name: "REST", GroupID: "g1"},
+				{ID: "c2", Name: "Website"},
+			},
+			spec: PageSpec{
+				Groups:     []GroupSpec{{Name: "API", Components: []ComponentSpec{{Name: "REST"}}}},
+				Components: []ComponentSpec{{Name: "Website"}},
+			},
+		},
+		{
+			name: "move between groups keeps the component",
+			groups: []ComponentGroup{
+				{ID: "g1", Name: "API", Components: []string{"c1", "c2"}},
+				{ID: "g2", Name: "Web", Components: []string{"c3"}},
+			},
+			components: []Component{
+				{ID: "c1", Name: "REST", GroupID: "g1"},
+				{ID: "c2", Name: "GraphQL", GroupID: "g1"},
+				{ID: "c3", Name: "Website", GroupID: "g2"},
+			},
+			spec: PageSpec{Groups: []GroupSpec{
+				{Name: "API", Components: []ComponentSpec{{Name: "REST"}}},
+				{Name: "Web", Components: []ComponentSpec{{Name: "Website"}, {Name: "GraphQL"}}},
+			}},
+			changes: []string{
+				`update group "API"`,
+				`update group "Web"`,
+			},
+		},
+		{
+			name: "components are created before their group",
+			spec: PageSpec{Groups: []GroupSpec{
+				{Name: "API", Components: []ComponentSpec{{Name: "REST"}}},
+			}},
+			changes: []string{
+				`create component "REST"`,
+				`create group "API"`,
+			},
+		},
+		{
+			name: "last unmatched component with the name is moved",
+			groups: []ComponentGroup{
+				{ID: "g1", Name: "EU", Components: []string{"c1"}},
+				{ID: "g2", Name: "US", Components: []string{"c2"}},
+			},
+			components: []Component{
+				{ID: "c1", Name: "API", GroupID: "g1"},
+				{ID: "c2", Name: "API", GroupID: "g2"},
+			},
+			spec: PageSpec{Groups: []GroupSpec{
+				{Name: "EU", Components: []ComponentSpec{{Name: "API"}}},
+				{Name: "APAC", Components: []ComponentSpec{{Name: "API"}}},
+			}},
+			changes: []string{
+				`create group "APAC"`,
+				`delete group "US"`,
+			},
+		},
+		{
+			name: "ambiguous move is a create and deletes",
+			groups: []ComponentGroup{
+				{ID: "g1", Name: "EU", Components: []string{"c1"}},
+				{ID: "g2", Name: "US", Components: []string{"c2"}},
+			},
+			components: []Component{
+				{ID: "c1", Name: "API", GroupID: "g1"},
+				{ID: "c2", Name: "API", GroupID: "g2"},
+			},
+			spec: PageSpec{Groups: []GroupSpec{
+				{Name: "APAC", Components: []ComponentSpec{{Name: "API"}}},
+			}},
+			changes: []string{
+				`create component "API"`,
+				`create group "APAC"`,
+				`delete component "API"`,
+				`delete component "API"`,
+				`delete group "EU"`,
+				`delete group "US"`,
+			},
+		},
+		{
+			name: "delete group with its components last",
+			groups: []ComponentGroup{
+				{ID: "g1", Name: "Legacy", Components: []string{"c1"}},
+			},
+			components: []Component{
+				{ID: "c1", Name: "Old", GroupID: "g1"},
+				{ID: "c2", Name: "Website", Description: "www"},
+			},
+			spec: PageSpec{Components: []ComponentSpec{{Name: "Website"}}},
+			changes: []string{
+				`update component "Website"`,
+				`delete component "Old"`,
+				`delete group "Legacy"`,
+			},
+		},
+		{
+			name: "move out of a deleted group",
+			groups: []ComponentGroup{
+				{ID: "g1", Name: "Legacy", Components: []string{"c1"}},
+			},
+			components: []Component{
+				{ID: "c1", Name: "Website", GroupID: "g1"},
+			},
+			spec: PageSpec{Components: []ComponentSpec{{Name: "Website"}}},
+			err:  `component "Website" cannot leave group "Legacy"`,
+		},
+		{
+			name: "several groups with the same name",
+			groups: []ComponentGroup{
+				{ID: "g1", Name: "API"},
+				{ID: "g2", Name: "API"},
+			},
+			err: `several groups named "API"`,
+		},
+		{
+			name: "several components with the same name",
+			components: []Component{
+				{ID: "c1", Name: "Website"},
+				{ID: "c2", Name: "Website"},
+			},
+			err: `several components named "Website"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planPageSpec(tt.spec, tt.groups, tt.components)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) || !errors.Is(err, ErrInvalidPageSpec) {
+					t.Fatalf("err = %v, want ErrInvalidPageSpec containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var changes []string
+			for _, c := range plan.Changes {
+				changes = append(changes, fmt.Sprintf("%s %s %q", c.Action, c.Kind, c.Name))
+			}
+			if !reflect.DeepEqual(changes, tt.changes) {
+				t.Errorf("changes = %q, want %q\n%s", changes, tt.changes, plan)
+			}
+		})
+	}
+}
+
+func TestPageSpecValidate(t *testing.T) {
+
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{name: "valid", json: `{"groups":[{"name":"API","components":[{"name":"REST"}]}],"components":[{"name":"REST"}]}`},
+		{name: "unknown field", json: `{"grups":[]}`, err: `unknown field "grups"`},
+		{name: "empty group", json: `{"groups":[{"name":"API","components":[]}]}`, err: `group "API" has no components`},
+		{name: "unnamed group", json: `{"groups":[{"components":[{"name":"REST"}]}]}`, err: "group without a name"},
+		{name: "duplicate group", json: `{"groups":[{"name":"A","components":[{"name":"x"}]},{"name":"A","components":[{"name":"y"}]}]}`, err: `duplicate group "A"`},
+		{name: "duplicate component", json: `{"components":[{"name":"x"},{"name":"x"}]}`, err: `duplicate component "x"`},
+		{name: "unnamed component", json: `{"components":[{"name":" "}]}`, err: "component without a name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePageSpec(strings.NewReader(tt.json))
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
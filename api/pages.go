@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	ReqPage struct {
+		Page PageUpdate `json:"page"`
+	}
+
+	// PageUpdate holds the page settings to change with UpdatePage. Only
+	// non-nil fields are sent, everything else is left untouched.
+	PageUpdate struct {
+		Name                     *string `json:"name,omitempty"`
+		Domain                   *string `json:"domain,omitempty"`
+		Subdomain                *string `json:"subdomain,omitempty"`
+		URL                      *string `json:"url,omitempty"`
+		Branding                 *string `json:"branding,omitempty"`
+		HiddenFromSearch         *bool   `json:"hidden_from_search,omitempty"`
+		ViewersMustBeTeamMembers *bool   `json:"viewers_must_be_team_members,omitempty"`
+		AllowPageSubscribers     *bool   `json:"allow_page_subscribers,omitempty"`
+		AllowIncidentSubscribers *bool   `json:"allow_incident_subscribers,omitempty"`
+		AllowEmailSubscribers    *bool   `json:"allow_email_subscribers,omitempty"`
+		AllowSmsSubscribers      *bool   `json:"allow_sms_subscribers,omitempty"`
+		AllowRssAtomFeeds        *bool   `json:"allow_rss_atom_feeds,omitempty"`
+		AllowWebhookSubscribers  *bool   `json:"allow_webhook_subscribers,omitempty"`
+		NotificationsFromEmail   *string `json:"notifications_from_email,omitempty"`
+		NotificationsEmailFooter *string `json:"notifications_email_footer,omitempty"`
+		TimeZone                 *string `json:"time_zone,omitempty"`
+		CSSBodyBackgroundColor   *string `json:"css_body_background_color,omitempty"`
+		CSSFontColor             *string `json:"css_font_color,omitempty"`
+		CSSLightFontColor        *string `json:"css_light_font_color,omitempty"`
+		CSSGreens                *string `json:"css_greens,omitempty"`
+		CSSYellows               *string `json:"css_yellows,omitempty"`
+		CSSOranges               *string `json:"css_oranges,omitempty"`
+		CSSBlues                 *string `json:"css_blues,omitempty"`
+		CSSReds                  *string `json:"css_reds,omitempty"`
+		CSSBorderColor           *string `json:"css_border_color,omitempty"`
+		CSSGraphColor            *string `json:"css_graph_color,omitempty"`
+		CSSLinkColor             *string `json:"css_link_color,omitempty"`
+		CSSNoData                *string `json:"css_no_data,omitempty"`
+	}
+
+	ReqStatusEmbedConfig struct {
+		StatusEmbedConfig StatusEmbedConfig `json:"status_embed_config"`
+	}
+
+	// StatusEmbedConfig configures the floating status widget embedded in
+	// other sites. Colors are hex codes such as "#e74c3c".
+	StatusEmbedConfig struct {
+		PageID                     string        `json:"page_id,omitempty"`
+		Position                   EmbedPosition `json:"position,omitempty"`
+		IncidentBackgroundColor    string        `json:"incident_background_color,omitempty"`
+		IncidentTextColor          string        `json:"incident_text_color,omitempty"`
+		MaintenanceBackgroundColor string        `json:"maintenance_background_color,omitempty"`
+		MaintenanceTextColor       string        `json:"maintenance_text_color,omitempty"`
+	}
+
+	EmbedPosition string
+)
+
+const (
+	EmbedBottomLeft  EmbedPosition = "bottom-left"
+	EmbedBottomRight EmbedPosition = "bottom-right"
+	EmbedTopLeft     EmbedPosition = "top-left"
+	EmbedTopRight    EmbedPosition = "top-right"
+)
+
+func (p EmbedPosition) String() string {
+
+	return string(p)
+}
+
+// String returns a pointer to v, for the optional fields of partial updates.
+func String(v string) *string {
+
+	return &v
+}
+
+// Bool returns a pointer to v, for the optional fields of partial updates.
+func Bool(v bool) *bool {
+
+	return &v
+}
+
+// GetPage fetches the page of the handle.
+func (s StatusPage) GetPage() (Page, error) {
+	return s.GetPageWithContext(context.Background())
+}
+
+func (s StatusPage) GetPageWithContext(ctx context.Context) (Page, error) {
+
+	var p Page
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath(""),
+		out:        &p,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return p, err
+
+}
+
+// UpdatePage changes the settings set in u and returns the updated page.
+func (s StatusPage) UpdatePage(u PageUpdate) (Page, error) {
+	return s.UpdatePageWithContext(context.Background(), u)
+}
+
+func (s StatusPage) UpdatePageWithContext(ctx context.Context, u PageUpdate) (Page, error) {
+
+	var p Page
+	s.Client.logger().Debug("updating page", "page_id", s.Page.ID)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPatch,
+		path:   s.pagePath(""),
+		body:   ReqPage{Page: u},
+		out:    &p,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return p, err
+	}
+	s.Client.logger().Info("page updated", "page_id", p.ID, "name", p.Name)
+
+	return p, nil
+
+}
+
+// GetStatusEmbedConfig fetches the status widget configuration of the page.
+func (s StatusPage) GetStatusEmbedConfig() (StatusEmbedConfig, error) {
+	return s.GetStatusEmbedConfigWithContext(context.Background())
+}
+
+func (s StatusPage) GetStatusEmbedConfigWithContext(ctx context.Context) (StatusEmbedConfig, error) {
+
+	var c StatusEmbedConfig
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/status_embed_config"),
+		out:        &c,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return c, err
+
+}
+
+// UpdateStatusEmbedConfig changes the non-empty fields of c and returns the
+// updated configuration.
+func (s StatusPage) UpdateStatusEmbedConfig(c StatusEmbedConfig) (StatusEmbedConfig, error) {
+	return s.UpdateStatusEmbedConfigWithContext(context.Background(), c)
+}
+
+func (s StatusPage) UpdateStatusEmbedConfigWithContext(ctx context.Context, c StatusEmbedConfig) (StatusEmbedConfig, error) {
+
+	c.PageID = ""
+
+	s.Client.logger().Debug("updating status embed config", "page_id", s.Page.ID)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPatch,
+		path:   s.pagePath("/status_embed_config"),
+		body:   ReqStatusEmbedConfig{StatusEmbedConfig: c},
+		out:    &c,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return c, err
+	}
+	s.Client.logger().Info("status embed config updated", "page_id", s.Page.ID)
+
+	return c, nil
+
+}
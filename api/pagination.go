@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// defaultPageSize is the page size requested when walking every page of a
+// list endpoint; it is the maximum accepted by Statuspage.
+const defaultPageSize = 100
+
+// ListOptions selects a page of a list endpoint. Page is 1-based. Statuspage
+// names the page size per_page on some endpoints and limit on others, so both
+// are sent when set.
+type ListOptions struct {
+	Page    int
+	PerPage int
+	Limit   int
+}
+
+func (o *ListOptions) values() url.Values {
+
+	v := url.Values{}
+	if o == nil {
+		return v
+	}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PerPage > 0 {
+		v.Set("per_page", strconv.Itoa(o.PerPage))
+	}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return v
+}
+
+// size returns the number of items a full page holds.
+func (o ListOptions) size() int {
+
+	switch {
+	case o.PerPage > 0 && o.Limit > 0 && o.Limit < o.PerPage:
+		return o.Limit
+	case o.PerPage > 0:
+		return o.PerPage
+	default:
+		return o.Limit
+	}
+}
+
+// pager walks the pages of a list endpoint lazily. fetch loads one page into
+// the buffer of the typed iterator owning the pager and returns how many items
+// it received.
+type pager struct {
+	opts  ListOptions
+	fetch func(ctx context.Context, opts ListOptions) (int, error)
+	done  bool
+	err   error
+}
+
+func newPager(opts *ListOptions, fetch func(ctx context.Context, opts ListOptions) (int, error)) pager {
+
+	p := pager{fetch: fetch}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.Page < 1 {
+		p.opts.Page = 1
+	}
+	if p.opts.PerPage == 0 && p.opts.Limit == 0 {
+		p.opts.PerPage = defaultPageSize
+		p.opts.Limit = defaultPageSize
+	}
+	return p
+}
+
+// next fetches the following page, returning false once the last page was
+// read or an error occurred.
+func (p *pager) next(ctx context.Context) bool {
+
+	if p.done {
+		return false
+	}
+	n, err := p.fetch(ctx, p.opts)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+	// a short page is the last one
+	if n == 0 || n < p.opts.size() {
+		p.done = true
+	}
+	p.opts.Page++
+	return n > 0
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPager(t *testing.T) {
+
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name  string
+		opts  *ListOptions
+		sizes []int // items returned by each fetch
+		fail  int   // 1-based fetch failing, 0 for none
+		pages []int // pages requested
+		items int
+		err   error
+	}{
+		{name: "single short page", sizes: []int{3}, pages: []int{1}, items: 3},
+		{name: "empty", sizes: []int{0}, pages: []int{1}},
+		{name: "full then short", sizes: []int{100, 100, 42}, pages: []int{1, 2, 3}, items: 242},
+		{name: "full then empty", sizes: []int{100, 0}, pages: []int{1, 2}, items: 100},
+		{name: "custom page size", opts: &ListOptions{PerPage: 2}, sizes: []int{2, 2, 1}, pages: []int{1, 2, 3}, items: 5},
+		{name: "limit only", opts: &ListOptions{Limit: 3}, sizes: []int{3, 2}, pages: []int{1, 2}, items: 5},
+		{name: "smaller limit wins", opts: &ListOptions{PerPage: 10, Limit: 2}, sizes: []int{2, 1}, pages: []int{1, 2}, items: 3},
+		{name: "start page", opts: &ListOptions{Page: 3, PerPage: 2}, sizes: []int{2, 0}, pages: []int{3, 4}, items: 2},
+		{name: "error stops", sizes: []int{100, 100}, fail: 2, pages: []int{1, 2}, items: 100, err: errBoom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				pages []int
+				items int
+			)
+			p := newPager(tt.opts, func(ctx context.Context, o ListOptions) (int, error) {
+				pages = append(pages, o.Page)
+				if len(pages) == tt.fail {
+					return 0, errBoom
+				}
+				n := tt.sizes[len(pages)-1]
+				items += n
+				return n, nil
+			})
+			for p.next(context.Background()) {
+			}
+			if p.next(context.Background()) {
+				t.Error("next returned true after the last page")
+			}
+
+			if !reflect.DeepEqual(pages, tt.pages) {
+				t.Errorf("fetched pages %v, want %v", pages, tt.pages)
+			}
+			if items != tt.items {
+				t.Errorf("got %d items, want %d", items, tt.items)
+			}
+			if p.err != tt.err {
+				t.Errorf("err = %v, want %v", p.err, tt.err)
+			}
+		})
+	}
+}
+
+func TestIterateComponents(t *testing.T) {
+
+	const total = 5
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		fmt.Fprint(w, "[")
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			if i > (page-1)*size {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id":"c%d"}`, i)
+		}
+		fmt.Fprint(w, "]")
+	}))
+	defer srv.Close()
+
+	s := newTestClient(srv).Page("p1")
+	it := s.IterateComponents(&ListOptions{PerPage: 2})
+	var ids []string
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Component().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"c0", "c1", "c2", "c3", "c4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got components %v, want %v", ids, want)
+	}
+	if want := []string{"page=1&per_page=2", "page=2&per_page=2", "page=3&per_page=2"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("got queries %v, want %v", queries, want)
+	}
+}
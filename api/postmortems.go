@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type (
+	reqPostmortem struct {
+		Postmortem interface{} `json:"postmortem"`
+	}
+
+	// Postmortem is the write-up attached to an incident. BodyDraft holds the
+	// unpublished text, Body the text shown on the page once published.
+	Postmortem struct {
+		PreviewKey         string     `json:"preview_key,omitempty"`
+		Body               string     `json:"body,omitempty"`
+		BodyUpdatedAt      *time.Time `json:"body_updated_at,omitempty"`
+		BodyDraft          string     `json:"body_draft,omitempty"`
+		BodyDraftUpdatedAt *time.Time `json:"body_draft_updated_at,omitempty"`
+		PublishedAt        *time.Time `json:"published_at,omitempty"`
+		NotifySubscribers  bool       `json:"notify_subscribers,omitempty"`
+		NotifyTwitter      bool       `json:"notify_twitter,omitempty"`
+		CustomTweet        string     `json:"custom_tweet,omitempty"`
+		CreatedAt          *time.Time `json:"created_at,omitempty"`
+		UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+	}
+
+	postmortemDraft struct {
+		BodyDraft string `json:"body_draft"`
+	}
+
+	// PostmortemPublishOptions selects who is told about a published
+	// postmortem.
+	PostmortemPublishOptions struct {
+		NotifySubscribers bool   `json:"notify_subscribers"`
+		NotifyTwitter     bool   `json:"notify_twitter"`
+		CustomTweet       string `json:"custom_tweet,omitempty"`
+	}
+)
+
+// GetPostmortem returns the postmortem of the incident with the given id.
+func (s StatusPage) GetPostmortem(incidentID string) (Postmortem, error) {
+	return s.GetPostmortemWithContext(context.Background(), incidentID)
+}
+
+func (s StatusPage) GetPostmortemWithContext(ctx context.Context, incidentID string) (Postmortem, error) {
+
+	var p Postmortem
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/incidents/%s/postmortem", incidentID),
+		out:        &p,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return p, err
+
+}
+
+// UpdatePostmortemDraft creates or replaces the draft postmortem of the
+// incident with the given id.
+func (s StatusPage) UpdatePostmortemDraft(incidentID, bodyDraft string) (Postmortem, error) {
+	return s.UpdatePostmortemDraftWithContext(context.Background(), incidentID, bodyDraft)
+}
+
+func (s StatusPage) UpdatePostmortemDraftWithContext(ctx context.Context, incidentID, bodyDraft string) (Postmortem, error) {
+
+	var p Postmortem
+	s.Client.logger().Debug("updating postmortem draft", "incident_id", incidentID)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodPut,
+		path:       s.pagePath("/incidents/%s/postmortem", incidentID),
+		body:       reqPostmortem{Postmortem: postmortemDraft{BodyDraft: bodyDraft}},
+		out:        &p,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+	if err != nil {
+		return p, err
+	}
+	s.Client.logger().Info("postmortem draft updated", "incident_id", incidentID)
+
+	return p, nil
+
+}
+
+// PublishPostmortem publishes the draft postmortem of the incident with the
+// given id.
+func (s StatusPage) PublishPostmortem(incidentID string, opts PostmortemPublishOptions) (Postmortem, error) {
+	return s.PublishPostmortemWithContext(context.Background(), incidentID, opts)
+}
+
+func (s StatusPage) PublishPostmortemWithContext(ctx context.Context, incidentID string, opts PostmortemPublishOptions) (Postmortem, error) {
+
+	var p Postmortem
+	s.Client.logger().Debug("publishing postmortem", "incident_id", incidentID)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPut,
+		path:   s.pagePath("/incidents/%s/postmortem/publish", incidentID),
+		body:   reqPostmortem{Postmortem: opts},
+		out:    &p,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return p, err
+	}
+	s.Client.logger().Info("postmortem published", "incident_id", incidentID)
+
+	return p, nil
+
+}
+
+// RevertPostmortem takes a published postmortem back to draft.
+func (s StatusPage) RevertPostmortem(incidentID string) (Postmortem, error) {
+	return s.RevertPostmortemWithContext(context.Background(), incidentID)
+}
+
+func (s StatusPage) RevertPostmortemWithContext(ctx context.Context, incidentID string) (Postmortem, error) {
+
+	var p Postmortem
+	s.Client.logger().Debug("reverting postmortem", "incident_id", incidentID)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPut,
+		path:   s.pagePath("/incidents/%s/postmortem/revert", incidentID),
+		out:    &p,
+		expect: http.StatusOK,
+	})
+	if err != nil {
+		return p, err
+	}
+	s.Client.logger().Info("postmortem reverted", "incident_id", incidentID)
+
+	return p, nil
+
+}
+
+// DeletePostmortem deletes the postmortem of the incident with the given id.
+func (s StatusPage) DeletePostmortem(incidentID string) error {
+	return s.DeletePostmortemWithContext(context.Background(), incidentID)
+}
+
+func (s StatusPage) DeletePostmortemWithContext(ctx context.Context, incidentID string) error {
+
+	s.Client.logger().Debug("deleting postmortem", "incident_id", incidentID)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/incidents/%s/postmortem", incidentID),
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+	s.Client.logger().Info("postmortem deleted", "incident_id", incidentID)
+	return nil
+
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type (
+	// PublicClient reads the public API every status page serves under
+	// /api/v2, which needs no token. It works for pages of any organization,
+	// e.g. the status pages of third-party vendors.
+	PublicClient struct {
+		client *Client
+	}
+
+	// PublicPage identifies the page a public API response belongs to.
+	PublicPage struct {
+		ID        string     `json:"id"`
+		Name      string     `json:"name"`
+		URL       string     `json:"url"`
+		TimeZone  string     `json:"time_zone"`
+		UpdatedAt *time.Time `json:"updated_at"`
+	}
+
+	// PageStatus is the overall status of a page. Indicator is ImpactNone,
+	// ImpactMinor, ImpactMajor or ImpactCritical.
+	PageStatus struct {
+		Indicator   Impact `json:"indicator"`
+		Description string `json:"description"`
+	}
+
+	// Summary is the status, components, unresolved incidents and upcoming
+	// or in progress maintenances of a page.
+	Summary struct {
+		Page                  PublicPage  `json:"page"`
+		Status                PageStatus  `json:"status"`
+		Components            []Component `json:"components"`
+		Incidents             []Incident  `json:"incidents"`
+		ScheduledMaintenances []Incident  `json:"scheduled_maintenances"`
+	}
+
+	// CacheValidators identify the version of a response so it is only
+	// downloaded again once it changed.
+	CacheValidators struct {
+		ETag         string
+		LastModified string
+	}
+
+	// Status is the overall status of a page.
+	Status struct {
+		Page   PublicPage `json:"page"`
+		Status PageStatus `json:"status"`
+	}
+
+	publicComponents struct {
+		Components []Component `json:"components"`
+	}
+
+	publicIncidents struct {
+		Incidents []Incident `json:"incidents"`
+	}
+
+	publicMaintenances struct {
+		ScheduledMaintenances []Incident `json:"scheduled_maintenances"`
+	}
+)
+
+// NewPublicClient returns a PublicClient reading the page served at pageURL,
+// e.g. "https://status.example.com" or "https://example.statuspage.io".
+// Options apply as for NewClient, except that no rate limit is set unless
+// given with WithRateLimit.
+func NewPublicClient(pageURL string, opts ...Option) *PublicClient {
+
+	opts = append([]Option{WithBaseURL(pageURL), WithRateLimit(0, 0)}, opts...)
+	return &PublicClient{client: NewClient("", opts...)}
+}
+
+// GetSummary returns the status, components, unresolved incidents and
+// upcoming maintenances of the page in a single call.
+func (p *PublicClient) GetSummary() (Summary, error) {
+	return p.GetSummaryWithContext(context.Background())
+}
+
+func (p *PublicClient) GetSummaryWithContext(ctx context.Context) (Summary, error) {
+
+	var s Summary
+	err := p.get(ctx, "/summary.json", &s)
+
+	return s, err
+
+}
+
+// GetSummaryIfChanged returns the summary of the page and true, unless the
+// page did not change since the response v identifies; it then returns an
+// empty summary, v and false. The returned validators identify the summary
+// and are meant for the next call, zero validators always fetch it.
+func (p *PublicClient) GetSummaryIfChanged(v CacheValidators) (Summary, CacheValidators, bool, error) {
+	return p.GetSummaryIfChangedWithContext(context.Background(), v)
+}
+
+func (p *PublicClient) GetSummaryIfChangedWithContext(ctx context.Context, v CacheValidators) (Summary, CacheValidators, bool, error) {
+
+	header := http.Header{}
+	if v.ETag != "" {
+		header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		header.Set("If-Modified-Since", v.LastModified)
+	}
+
+	var (
+		s   Summary
+		rsp response
+	)
+	err := p.client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       publicPath("/summary.json"),
+		out:        &s,
+		expect:     http.StatusOK,
+		idempotent: true,
+		header:     header,
+		response:   &rsp,
+	})
+	if err != nil || rsp.status == http.StatusNotModified {
+		return s, v, false, err
+	}
+
+	next := CacheValidators{ETag: rsp.header.Get("ETag"), LastModified: rsp.header.Get("Last-Modified")}
+	return s, next, true, nil
+
+}
+
+// GetStatus returns the overall status of the page.
+func (p *PublicClient) GetStatus() (Status, error) {
+	return p.GetStatusWithContext(context.Background())
+}
+
+func (p *PublicClient) GetStatusWithContext(ctx context.Context) (Status, error) {
+
+	var s Status
+	err := p.get(ctx, "/status.json", &s)
+
+	return s, err
+
+}
+
+// GetComponents returns the components of the page, groups included.
+func (p *PublicClient) GetComponents() ([]Component, error) {
+	return p.GetComponentsWithContext(context.Background())
+}
+
+func (p *PublicClient) GetComponentsWithContext(ctx context.Context) ([]Component, error) {
+
+	var c publicComponents
+	err := p.get(ctx, "/components.json", &c)
+
+	return c.Components, err
+
+}
+
+// GetUnresolvedIncidents returns the incidents of the page that are not
+// resolved yet.
+func (p *PublicClient) GetUnresolvedIncidents() ([]Incident, error) {
+	return p.GetUnresolvedIncidentsWithContext(context.Background())
+}
+
+func (p *PublicClient) GetUnresolvedIncidentsWithContext(ctx context.Context) ([]Incident, error) {
+
+	var i publicIncidents
+	err := p.get(ctx, "/incidents/unresolved.json", &i)
+
+	return i.Incidents, err
+
+}
+
+// GetIncidents returns the 50 most recent incidents of the page.
+func (p *PublicClient) GetIncidents() ([]Incident, error) {
+	return p.GetIncidentsWithContext(context.Background())
+}
+
+func (p *PublicClient) GetIncidentsWithContext(ctx context.Context) ([]Incident, error) {
+
+	var i publicIncidents
+	err := p.get(ctx, "/incidents.json", &i)
+
+	return i.Incidents, err
+
+}
+
+// GetUpcomingMaintenances returns the maintenances of the page that have not
+// started yet.
+func (p *PublicClient) GetUpcomingMaintenances() ([]Incident, error) {
+	return p.GetUpcomingMaintenancesWithContext(context.Background())
+}
+
+func (p *PublicClient) GetUpcomingMaintenancesWithContext(ctx context.Context) ([]Incident, error) {
+
+	return p.maintenances(ctx, "/scheduled-maintenances/upcoming.json")
+}
+
+// GetActiveMaintenances returns the maintenances of the page that are in
+// progress or verifying.
+func (p *PublicClient) GetActiveMaintenances() ([]Incident, error) {
+	return p.GetActiveMaintenancesWithContext(context.Background())
+}
+
+func (p *PublicClient) GetActiveMaintenancesWithContext(ctx context.Context) ([]Incident, error) {
+
+	return p.maintenances(ctx, "/scheduled-maintenances/active.json")
+}
+
+// GetScheduledMaintenances returns the 50 most recent maintenances of the
+// page, whatever their status.
+func (p *PublicClient) GetScheduledMaintenances() ([]Incident, error) {
+	return p.GetScheduledMaintenancesWithContext(context.Background())
+}
+
+func (p *PublicClient) GetScheduledMaintenancesWithContext(ctx context.Context) ([]Incident, error) {
+
+	return p.maintenances(ctx, "/scheduled-maintenances.json")
+}
+
+func (p *PublicClient) maintenances(ctx context.Context, path string) ([]Incident, error) {
+
+	var m publicMaintenances
+	err := p.get(ctx, path, &m)
+
+	return m.ScheduledMaintenances, err
+}
+
+func (p *PublicClient) get(ctx context.Context, path string, out interface{}) error {
+
+	return p.client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       publicPath(path),
+		out:        out,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+}
+
+// publicPath returns the path of a public API endpoint.
+func publicPath(path string) string {
+
+	return "/api/v2" + path
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const publicSummaryJSON = `{
+  "page": {
+    "id": "kctbh9vrtdwd",
+    "name": "GitHub",
+    "url": "https://www.githubstatus.com",
+    "time_zone": "Etc/UTC",
+    "updated_at": "2020-09-15T09:04:18.325Z"
+  },
+  "components": [
+    {
+      "id": "8l4ygp009s5s",
+      "name": "Git Operations",
+      "status": "partial_outage",
+      "created_at": "2017-01-31T20:05:05.370Z",
+      "updated_at": "2020-09-15T09:04:18.308Z",
+      "position": 1,
+      "description": "Performance of git clones, pulls, pushes, and associated operations",
+      "showcase": false,
+      "start_date": null,
+      "group_id": "g0z5fcp5mvdq",
+      "page_id": "kctbh9vrtdwd",
+      "group": false,
+      "only_show_if_degraded": false
+    },
+    {
+      "id": "g0z5fcp5mvdq",
+      "name": "Core",
+      "status": "partial_outage",
+      "created_at": "2017-01-31T20:01:46.621Z",
+      "updated_at": "2020-09-15T09:04:18.308Z",
+      "position": 2,
+      "description": null,
+      "showcase": false,
+      "start_date": null,
+      "group_id": null,
+      "page_id": "kctbh9vrtdwd",
+      "group": true,
+      "only_show_if_degraded": false,
+      "components": ["8l4ygp009s5s"]
+    }
+  ],
+  "incidents": [
+    {
+      "id": "p3zxwxbvbzq3",
+      "name": "Incident on 2020-09-15 09:02 UTC",
+      "status": "investigating",
+      "created_at": "2020-09-15T09:02:35.582Z",
+      "updated_at": "2020-09-15T09:04:18.317Z",
+      "monitoring_at": null,
+      "resolved_at": null,
+      "impact": "minor",
+      "shortlink": "https://stspg.io/x0b1",
+      "started_at": "2020-09-15T09:02:35.576Z",
+      "page_id": "kctbh9vrtdwd",
+      "incident_updates": [
+        {
+          "id": "jrv1ssjx3v5d",
+          "status": "investigating",
+          "body": "We are investigating reports of degraded performance for Git Operations.",
+          "incident_id": "p3zxwxbvbzq3",
+          "created_at": "2020-09-15T09:04:18.321Z",
+          "updated_at": "2020-09-15T09:04:18.321Z",
+          "display_at": "2020-09-15T09:04:18.321Z",
+          "affected_components": [
+            {"code": "8l4ygp009s5s", "name": "Git Operations", "old_status": "operational", "new_status": "partial_outage"}
+          ],
+          "deliver_notifications": true,
+          "custom_tweet": null,
+          "tweet_id": null
+        }
+      ],
+      "components": [
+        {"id": "8l4ygp009s5s", "name": "Git Operations", "status": "partial_outage", "group": false, "group_id": "g0z5fcp5mvdq"}
+      ]
+    }
+  ],
+  "scheduled_maintenances": [
+    {
+      "id": "w1jtyh0z6bsy",
+      "name": "Database upgrade",
+      "status": "scheduled",
+      "impact": "maintenance",
+      "scheduled_for": "2020-09-20T02:00:00.000Z",
+      "scheduled_until": "2020-09-20T04:00:00.000Z",
+      "incident_updates": [],
+      "components": []
+    }
+  ],
+  "status": {
+    "indicator": "minor",
+    "description": "Partially Degraded Service"
+  }
+}`
+
+const publicComponentsJSON = `{
+  "page": {
+    "id": "kctbh9vrtdwd",
+    "name": "GitHub",
+    "url": "https://www.githubstatus.com",
+    "time_zone": "Etc/UTC",
+    "updated_at": "2020-09-15T09:04:18.325Z"
+  },
+  "components": [
+    {
+      "id": "8l4ygp009s5s",
+      "name": "Git Operations",
+      "status": "operational",
+      "created_at": "2017-01-31T20:05:05.370Z",
+      "updated_at": "2020-09-15T09:04:18.308Z",
+      "position": 1,
+      "description": null,
+      "showcase": true,
+      "start_date": "2017-01-31",
+      "group_id": null,
+      "page_id": "kctbh9vrtdwd",
+      "group": false,
+      "only_show_if_degraded": false
+    },
+    {
+      "id": "brv1bkgrwx7q",
+      "name": "API Requests",
+      "status": "degraded_performance",
+      "created_at": "2017-01-31T20:01:46.621Z",
+      "updated_at": "2020-09-15T09:04:18.308Z",
+      "position": 2,
+      "description": "Requests for GitHub APIs",
+      "showcase": false,
+      "start_date": null,
+      "group_id": null,
+      "page_id": "kctbh9vrtdwd",
+      "group": false,
+      "only_show_if_degraded": true
+    }
+  ]
+}`
+
+// newPublicServer serves body at path of the public API, rejecting
+// authenticated requests.
+func newPublicServer(t *testing.T, path, body string) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2"+path {
+			t.Errorf("requested %s, want /api/v2%s", r.URL.Path, path)
+		}
+		if h := r.Header.Get("Authorization"); h != "" {
+			t.Errorf("sent Authorization %q, want none", h)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+}
+
+func TestPublicClientGetSummary(t *testing.T) {
+
+	srv := newPublicServer(t, "/summary.json", publicSummaryJSON)
+	defer srv.Close()
+
+	s, err := NewPublicClient(srv.URL).GetSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := time.Date(2020, 9, 15, 9, 4, 18, 325000000, time.UTC)
+	if s.Page.ID != "kctbh9vrtdwd" || s.Page.Name != "GitHub" || s.Page.TimeZone != "Etc/UTC" {
+		t.Errorf("page = %+v", s.Page)
+	}
+	if s.Page.UpdatedAt == nil || !s.Page.UpdatedAt.Equal(updated) {
+		t.Errorf("page updated at %v, want %v", s.Page.UpdatedAt, updated)
+	}
+	if s.Status != (PageStatus{Indicator: ImpactMinor, Description: "Partially Degraded Service"}) {
+		t.Errorf("status = %+v", s.Status)
+	}
+
+	if len(s.Components) != 2 {
+		t.Fatalf("got %d components, want 2", len(s.Components))
+	}
+	c := s.Components[0]
+	if c.ID != "8l4ygp009s5s" || c.Status != ComponentStatusPartialOutage || c.GroupID != "g0z5fcp5mvdq" || c.Group || c.Position != 1 {
+		t.Errorf("component = %+v", c)
+	}
+	if g := s.Components[1]; !g.Group || g.Name != "Core" || g.Description != "" {
+		t.Errorf("group = %+v", g)
+	}
+
+	if len(s.Incidents) != 1 {
+		t.Fatalf("got %d incidents, want 1", len(s.Incidents))
+	}
+	i := s.Incidents[0]
+	if i.Status != IncidentStatusInvestigating || i.Impact != ImpactMinor || i.Shortlink != "https://stspg.io/x0b1" {
+		t.Errorf("incident = %+v", i)
+	}
+	if len(i.Components) != 1 || i.Components[0].Status != ComponentStatusPartialOutage {
+		t.Errorf("incident components = %+v", i.Components)
+	}
+	if len(i.IncidentUpdates) != 1 {
+		t.Fatalf("got %d incident updates, want 1", len(i.IncidentUpdates))
+	}
+	u := i.IncidentUpdates[0]
+	if u.IncidentID != i.ID || !u.DeliverNotifications || len(u.AffectedComponents) != 1 {
+		t.Errorf("incident update = %+v", u)
+	}
+	if a := u.AffectedComponents[0]; a.Code != "8l4ygp009s5s" || a.OldStatus != ComponentStatusOperational || a.NewStatus != ComponentStatusPartialOutage {
+		t.Errorf("affected component = %+v", a)
+	}
+
+	if len(s.ScheduledMaintenances) != 1 {
+		t.Fatalf("got %d maintenances, want 1", len(s.ScheduledMaintenances))
+	}
+	m := s.ScheduledMaintenances[0]
+	until := time.Date(2020, 9, 20, 4, 0, 0, 0, time.UTC)
+	if m.Status != IncidentStatusScheduled || m.Impact != ImpactMaintenance || m.ScheduledUntil == nil || !m.ScheduledUntil.Equal(until) {
+		t.Errorf("maintenance = %+v", m)
+	}
+}
+
+func TestPublicClientGetComponents(t *testing.T) {
+
+	srv := newPublicServer(t, "/components.json", publicComponentsJSON)
+	defer srv.Close()
+
+	components, err := NewPublicClient(srv.URL).GetComponents()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		id       string
+		name     string
+		status   ComponentStatus
+		showcase bool
+		degraded bool
+		start    string
+	}{
+		{"8l4ygp009s5s", "Git Operations", ComponentStatusOperational, true, false, "2017-01-31"},
+		{"brv1bkgrwx7q", "API Requests", ComponentStatusDegradedPerformance, false, true, ""},
+	}
+	if len(components) != len(want) {
+		t.Fatalf("got %d components, want %d", len(components), len(want))
+	}
+	for i, w := range want {
+		c := components[i]
+		if c.ID != w.id || c.Name != w.name || c.Status != w.status || c.Showcase != w.showcase || c.OnlyShowIfDegraded != w.degraded || c.StartDate != w.start {
+			t.Errorf("component %d = %+v, want %+v", i, c, w)
+		}
+		if c.PageID != "kctbh9vrtdwd" || c.CreatedAt == nil {
+			t.Errorf("component %d = %+v, want its page and creation time", i, c)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		calls int
+		// the last call must wait at least min and less than max
+		min, max time.Duration
+	}{
+		{name: "first call", rate: 10, burst: 1, calls: 1, max: 20 * time.Millisecond},
+		{name: "burst", rate: 10, burst: 3, calls: 3, max: 20 * time.Millisecond},
+		{name: "over burst", rate: 10, burst: 1, calls: 2, min: 80 * time.Millisecond, max: 150 * time.Millisecond},
+		{name: "over larger burst", rate: 10, burst: 2, calls: 3, min: 80 * time.Millisecond, max: 150 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(tt.rate, tt.burst)
+			for i := 0; i < tt.calls-1; i++ {
+				if err := l.wait(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			start := time.Now()
+			if err := l.wait(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if d := time.Since(start); d < tt.min || d >= tt.max {
+				t.Errorf("waited %s, want between %s and %s", d, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+
+	if l := newRateLimiter(0, 5); l != nil {
+		t.Fatalf("newRateLimiter(0) = %v, want nil", l)
+	}
+
+	var l *rateLimiter
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := l.wait(ctx); err != nil {
+		t.Fatalf("wait = %v, want nil", err)
+	}
+	cancel()
+	if err := l.wait(ctx); err != context.Canceled {
+		t.Fatalf("wait = %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimiterCancelReturnsToken(t *testing.T) {
+
+	l := newRateLimiter(10, 1)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// this call reserves the next token, then gives it back when canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("wait = %v, want context.DeadlineExceeded", err)
+	}
+
+	// with the token back the next call waits for one token, not two
+	start := time.Now()
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d >= 150*time.Millisecond {
+		t.Errorf("waited %s after a canceled wait, want less than 150ms", d)
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// request describes a single call to the Statuspage API.
+//
+// query, when set, is appended to path. body, when set, is encoded as JSON and sent with a JSON content type. out,
+// when set, receives the decoded response body. expect is the status code the
+// call must return; zero accepts any 2xx status. idempotent marks reads and
+// writes that leave the page in the same state when repeated, only those are
+// retried after a server error or a network failure.
+//
+// header is sent along with the request. When it makes the request
+// conditional, with If-None-Match or If-Modified-Since, a 304 response is a
+// success and out is left untouched. response, when set, receives the status
+// and headers of the last response.
+type request struct {
+	method     string
+	path       string
+	query      url.Values
+	body       interface{}
+	out        interface{}
+	expect     int
+	idempotent bool
+	header     http.Header
+	response   *response
+}
+
+// response is the status and headers of a response.
+type response struct {
+	status int
+	header http.Header
+}
+
+// do executes req and is the single path every resource method goes through,
+// so authentication, rate limiting, retries, status checks, decoding and
+// closing of the response body behave the same for all of them.
+func (c *Client) do(ctx context.Context, req request) error {
+
+	var payload []byte
+	if req.body != nil {
+		b, err := json.Marshal(req.body)
+		if err != nil {
+			return err
+		}
+		payload = b
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	for attempt := 0; ; attempt++ {
+		b, err = c.send(ctx, req, payload, attempt)
+		delay, retry := c.Config.Retry.backoff(req.idempotent, attempt, err)
+		if !retry {
+			break
+		}
+		c.logger().Debug("retrying statuspage request",
+			"method", req.method,
+			"path", req.path,
+			"status", errorStatus(err),
+			"attempt", attempt+1,
+			"delay", delay,
+		)
+		if serr := sleep(ctx, delay); serr != nil {
+			err = serr
+			break
+		}
+	}
+	if err != nil {
+		c.logger().Debug("statuspage request failed", "method", req.method, "path", req.path, "status", errorStatus(err))
+		return err
+	}
+
+	if req.out == nil || len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, req.out); err != nil {
+		c.logger().Debug("unable to decode statuspage response", "method", req.method, "path", req.path)
+		return fmt.Errorf("unable to decode response of %s %s: %s", req.method, req.path, err)
+	}
+
+	return nil
+
+}
+
+// send makes a single attempt of req and returns the response body. An
+// unexpected status is reported as an *APIError.
+func (c *Client) send(ctx context.Context, req request, payload []byte, attempt int) ([]byte, error) {
+
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+		c.logger().Debug("statuspage request body", "method", req.method, "path", req.path, "body", string(payload))
+	}
+	u := c.Config.URL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+	r, err := http.NewRequestWithContext(ctx, req.method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.header {
+		r.Header[k] = v
+	}
+	if c.Config.Token != "" {
+		r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", c.Config.Token))
+	}
+	if c.Config.UserAgent != "" {
+		r.Header.Set("User-Agent", c.Config.UserAgent)
+	}
+	if payload != nil {
+		r.Header.Add("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	rsp, err := c.httpclient.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	b, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.logger().Debug("statuspage request",
+		"method", req.method,
+		"path", req.path,
+		"status", rsp.StatusCode,
+		"duration", time.Since(start),
+		"attempt", attempt+1,
+	)
+	c.logger().Debug("statuspage response body", "method", req.method, "path", req.path, "body", string(b))
+	if req.response != nil {
+		*req.response = response{status: rsp.StatusCode, header: rsp.Header}
+	}
+
+	if rsp.StatusCode == http.StatusNotModified && conditional(req.header) {
+		return nil, nil
+	}
+	if !expected(rsp.StatusCode, req.expect) {
+		return b, newAPIError(rsp, b)
+	}
+
+	return b, nil
+
+}
+
+func expected(status, expect int) bool {
+
+	if expect == 0 {
+		return status >= 200 && status < 300
+	}
+	return status == expect
+}
+
+// errorStatus returns the status code of an *APIError, zero for other
+// errors. Errors are not logged as such since an APIError may carry the
+// response body; callers decide how to report them.
+func errorStatus(err error) int {
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func conditional(h http.Header) bool {
+
+	return h.Get("If-None-Match") != "" || h.Get("If-Modified-Since") != ""
+}
+
+// pagePath returns the API path of a resource under the handle's page.
+func (s StatusPage) pagePath(format string, a ...interface{}) string {
+
+	return fmt.Sprintf("/v1/pages/%s", s.Page.ID) + fmt.Sprintf(format, a...)
+}
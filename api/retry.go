@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how the Client retries failed requests.
+//
+// Rate limited responses (420 and 429) are retried for every request since
+// the API rejected them before doing any work, unless Retry-After asks to wait
+// longer than MaxBackoff. Server errors and network failures are only retried
+// for reads and for writes that can safely be repeated, so a call such as
+// CreateIncident or UpdateIncident, which notifies subscribers, is never sent
+// twice after it may have been processed.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt, zero
+	// disables retrying.
+	MaxRetries int
+	// MinBackoff is the base delay, doubled on every retry.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts. A rate limited
+	// response asking to wait longer is returned instead of retried.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used by New.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+}
+
+// backoff reports whether a request that failed with err on the given attempt
+// (starting at zero) should be retried, and after how long. idempotent tells
+// whether the request may be repeated after the API possibly processed it.
+func (p RetryPolicy) backoff(idempotent bool, attempt int, err error) (time.Duration, bool) {
+
+	if err == nil || attempt >= p.MaxRetries {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case IsRateLimited(apiErr):
+			if p.MaxBackoff > 0 && apiErr.RetryAfter > p.MaxBackoff {
+				return 0, false
+			}
+			if apiErr.RetryAfter > 0 {
+				return apiErr.RetryAfter, true
+			}
+		case apiErr.StatusCode >= 500 && idempotent:
+		default:
+			return 0, false
+		}
+	} else if !idempotent {
+		return 0, false
+	}
+
+	return p.delay(attempt), true
+}
+
+// delay returns the exponential backoff for attempt with full jitter on its
+// upper half.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+
+	d := p.MinBackoff
+	for i := 0; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+
+	policy := RetryPolicy{MaxRetries: 3, MinBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second}
+	apiErr := func(status int, retryAfter time.Duration) error {
+		return &APIError{StatusCode: status, RetryAfter: retryAfter}
+	}
+	netErr := errors.New("connection reset by peer")
+
+	tests := []struct {
+		name       string
+		idempotent bool
+		attempt    int
+		err        error
+		retry      bool
+		// the delay must be within [min, max]
+		min, max time.Duration
+	}{
+		{name: "success", idempotent: true, err: nil},
+		{name: "idempotent 503", idempotent: true, err: apiErr(503, 0), retry: true, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{name: "idempotent 502 third attempt", idempotent: true, attempt: 2, err: apiErr(502, 0), retry: true, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{name: "write 500", err: apiErr(500, 0)},
+		{name: "write 502", err: apiErr(502, 0)},
+		{name: "idempotent 404", idempotent: true, err: apiErr(404, 0)},
+		{name: "idempotent 422", idempotent: true, err: apiErr(422, 0)},
+		{name: "write 429 retry after", err: apiErr(429, 7*time.Second), retry: true, min: 7 * time.Second, max: 7 * time.Second},
+		{name: "write 420 retry after", err: apiErr(StatusEnhanceYourCalm, 2*time.Second), retry: true, min: 2 * time.Second, max: 2 * time.Second},
+		{name: "write 429 no retry after", err: apiErr(429, 0), retry: true, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{name: "retry after beyond max backoff", idempotent: true, err: apiErr(429, time.Hour)},
+		{name: "retry after at max backoff", idempotent: true, err: apiErr(429, 10*time.Second), retry: true, min: 10 * time.Second, max: 10 * time.Second},
+		{name: "capped backoff", idempotent: true, attempt: 2, err: apiErr(503, 0), retry: true, min: 200 * time.Millisecond, max: 400 * time.Millisecond},
+		{name: "idempotent network error", idempotent: true, err: netErr, retry: true, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{name: "write network error", err: netErr},
+		{name: "canceled", idempotent: true, err: context.Canceled},
+		{name: "deadline", idempotent: true, err: context.DeadlineExceeded},
+		{name: "retries exhausted", idempotent: true, attempt: 3, err: apiErr(503, 0)},
+		{name: "rate limit retries exhausted", attempt: 3, err: apiErr(429, time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := policy.backoff(tt.idempotent, tt.attempt, tt.err)
+			if retry != tt.retry {
+				t.Fatalf("retry = %t, want %t", retry, tt.retry)
+			}
+			if retry && (delay < tt.min || delay > tt.max) {
+				t.Errorf("delay = %s, want between %s and %s", delay, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelayCapped(t *testing.T) {
+
+	policy := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for attempt := 0; attempt < 70; attempt++ {
+		if d := policy.delay(attempt); d > policy.MaxBackoff || d <= 0 {
+			t.Fatalf("delay(%d) = %s, want in (0, %s]", attempt, d, policy.MaxBackoff)
+		}
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		idempotent bool
+		statuses   []int
+		requests   int
+		wantErr    bool
+	}{
+		{name: "idempotent retried until success", idempotent: true, statuses: []int{503, 502, 200}, requests: 3},
+		{name: "idempotent gives up", idempotent: true, statuses: []int{503, 503, 503, 503}, requests: 3, wantErr: true},
+		{name: "write not retried on 500", statuses: []int{500, 200}, requests: 1, wantErr: true},
+		{name: "write retried on 429", statuses: []int{429, 201}, requests: 2},
+		{name: "write retried on 420", statuses: []int{StatusEnhanceYourCalm, 201}, requests: 2},
+		{name: "not found not retried", idempotent: true, statuses: []int{404, 200}, requests: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt32(&n, 1) - 1
+				w.WriteHeader(tt.statuses[i])
+				w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			c := newTestClient(srv, WithRetryPolicy(RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+			err := c.do(context.Background(), request{method: http.MethodPut, path: "/v1/pages", body: struct{}{}, idempotent: tt.idempotent})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %t", err, tt.wantErr)
+			}
+			if int(n) != tt.requests {
+				t.Errorf("sent %d requests, want %d", n, tt.requests)
+			}
+		})
+	}
+}
+
+func TestClientRetriesByCall(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		call     func(s StatusPage) error
+		requests int
+	}{
+		{
+			name: "get components",
+			call: func(s StatusPage) error {
+				_, err := s.GetComponents()
+				return err
+			},
+			requests: 3,
+		},
+		{
+			name: "update component",
+			call: func(s StatusPage) error {
+				_, err := s.UpdateComponent(Component{ID: "c1", Name: "API"})
+				return err
+			},
+			requests: 3,
+		},
+		{
+			name: "update incident notifies subscribers",
+			call: func(s StatusPage) error {
+				_, err := s.UpdateIncident(Incident{ID: "i1", Status: IncidentStatusMonitoring})
+				return err
+			},
+			requests: 1,
+		},
+		{
+			name: "publish postmortem notifies subscribers",
+			call: func(s StatusPage) error {
+				_, err := s.PublishPostmortem("i1", PostmortemPublishOptions{NotifySubscribers: true})
+				return err
+			},
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&n, 1)
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+			defer srv.Close()
+
+			c := newTestClient(srv, WithRetryPolicy(RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+			if err := tt.call(c.Page("p1")); err == nil {
+				t.Fatal("call succeeded, want the 502")
+			}
+			if int(n) != tt.requests {
+				t.Errorf("sent %d requests, want %d", n, tt.requests)
+			}
+		})
+	}
+}
+
+// cancelLogger cancels a context when the client logs that it will retry.
+type cancelLogger struct {
+	nopLogger
+	cancel context.CancelFunc
+}
+
+func (l cancelLogger) Debug(msg string, args ...interface{}) {
+
+	if msg == "retrying statuspage request" {
+		l.cancel()
+	}
+}
+
+func TestClientRetryCanceled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	// the context is canceled once the 503 was read, while the client waits
+	// before retrying
+	c := newTestClient(srv,
+		WithRetryPolicy(RetryPolicy{MaxRetries: 3, MinBackoff: time.Hour, MaxBackoff: time.Hour}),
+		WithLogger(cancelLogger{cancel: cancel}),
+	)
+	_, err := c.GetPagesWithContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
@@ -1,10 +1,8 @@
 package api
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
 	"time"
 )
@@ -69,55 +67,95 @@ type (
 	Client struct {
 		Config     *Config
 		httpclient *http.Client
+		limiter    *rateLimiter
 	}
 
 	Config struct {
-		URL     string
-		Token   string
-		Timeout time.Duration
+		URL       string
+		Token     string
+		Timeout   time.Duration
+		UserAgent string
+		// Retry controls how failed requests are retried.
+		Retry RetryPolicy
+		// RateLimit is the number of requests per second the client sends,
+		// with bursts of up to RateBurst requests. Zero disables the client
+		// side limit.
+		RateLimit float64
+		RateBurst int
+		// Logger receives the diagnostics of the client, nothing is logged
+		// when it is nil.
+		Logger Logger
 	}
 )
 
+// New returns a StatusPage talking to url with token. It is kept for
+// compatibility, NewClient offers every configuration option.
 func New(url, token string, timeout time.Duration) StatusPage {
-	c := &Config{URL: url,
-		Token:   token,
-		Timeout: timeout,
-	}
 
 	return StatusPage{
-		Client: &Client{
-			Config:     c,
-			httpclient: &http.Client{},
-		},
-		Page: Page{},
+		Client: NewClient(token, WithBaseURL(url), WithTimeout(timeout)),
+		Page:   Page{},
 	}
 }
 
-func (c Client) GetPages() (Pages, error) {
+func (c *Client) GetPages() (Pages, error) {
+	return c.GetPagesWithContext(context.Background())
+}
+
+func (c *Client) GetPagesWithContext(ctx context.Context) (Pages, error) {
 
 	pages := Pages{}
-	url := fmt.Sprintf("%s/v1/pages", c.Config.URL)
+	err := c.do(ctx, request{
+		method:     http.MethodGet,
+		path:       "/v1/pages",
+		out:        &pages,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
 
-	r, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return pages, err
-	}
-	r.Header.Add("Authorization", fmt.Sprintf("OAuth %s", c.Config.Token))
-	rsp, err := c.httpclient.Do(r)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return pages, err
-	}
+	return pages, err
 
-	body, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		log.Printf("Error %s", err)
-		return pages, err
-	}
+}
+
+// Page returns a handle scoped to the page with the given id without
+// querying the API. Handles share the Client, which is safe for concurrent
+// use, so one Client can manage many pages from different goroutines.
+func (c *Client) Page(id string) StatusPage {
+
+	return StatusPage{Client: c, Page: Page{ID: id}}
+}
 
-	json.Unmarshal(body, &pages)
+// PageByName returns a handle for the page called name.
+func (c *Client) PageByName(name string) (StatusPage, error) {
+	return c.PageByNameWithContext(context.Background(), name)
+}
+
+func (c *Client) PageByNameWithContext(ctx context.Context, name string) (StatusPage, error) {
+
+	return c.findPage(ctx, func(p Page) bool { return p.Name == name }, "name", name)
+}
+
+// PageBySubdomain returns a handle for the page hosted on subdomain.
+func (c *Client) PageBySubdomain(subdomain string) (StatusPage, error) {
+	return c.PageBySubdomainWithContext(context.Background(), subdomain)
+}
+
+func (c *Client) PageBySubdomainWithContext(ctx context.Context, subdomain string) (StatusPage, error) {
 
-	return pages, nil
+	return c.findPage(ctx, func(p Page) bool { return p.Subdomain == subdomain }, "subdomain", subdomain)
+}
+
+func (c *Client) findPage(ctx context.Context, match func(Page) bool, field, value string) (StatusPage, error) {
+
+	pages, err := c.GetPagesWithContext(ctx)
+	if err != nil {
+		return StatusPage{}, err
+	}
+	for _, p := range pages {
+		if match(p.Page) {
+			return StatusPage{Client: c, Page: p.Page}, nil
+		}
+	}
 
+	return StatusPage{}, fmt.Errorf("unable to find page with %s %s: %w", field, value, ErrNotFound)
 }
@@ -0,0 +1,388 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type (
+	SubscriberType  string
+	SubscriberState string
+
+	ReqSubscriber struct {
+		Subscriber Subscriber `json:"subscriber"`
+	}
+
+	Subscriber struct {
+		ID                           string     `json:"id,omitempty"`
+		Mode                         string     `json:"mode,omitempty"`
+		Email                        string     `json:"email,omitempty"`
+		Endpoint                     string     `json:"endpoint,omitempty"`
+		PhoneNumber                  string     `json:"phone_number,omitempty"`
+		PhoneCountry                 string     `json:"phone_country,omitempty"`
+		DisplayPhoneNumber           string     `json:"display_phone_number,omitempty"`
+		ObfuscatedChannelName        string     `json:"obfuscated_channel_name,omitempty"`
+		WorkspaceName                string     `json:"workspace_name,omitempty"`
+		Components                   []string   `json:"components,omitempty"`
+		ComponentIDs                 []string   `json:"component_ids,omitempty"`
+		PageAccessUserID             string     `json:"page_access_user_id,omitempty"`
+		SkipConfirmationNotification bool       `json:"skip_confirmation_notification,omitempty"`
+		QuarantinedAt                *time.Time `json:"quarantined_at,omitempty"`
+		PurgeAt                      *time.Time `json:"purge_at,omitempty"`
+		CreatedAt                    *time.Time `json:"created_at,omitempty"`
+	}
+
+	// SubscriberListOptions filters the subscribers returned by the list
+	// methods. Empty fields do not filter.
+	SubscriberListOptions struct {
+		ListOptions
+		// Query matches email addresses and phone numbers.
+		Query string
+		Type  SubscriberType
+		State SubscriberState
+	}
+
+	// subscriberBulk is the body of the calls acting on many subscribers at
+	// once.
+	subscriberBulk struct {
+		Subscribers                    []string       `json:"subscribers"`
+		Type                           SubscriberType `json:"type,omitempty"`
+		SkipUnsubscriptionNotification bool           `json:"skip_unsubscription_notification,omitempty"`
+	}
+)
+
+const (
+	SubscriberTypeEmail              SubscriberType = "email"
+	SubscriberTypeSMS                SubscriberType = "sms"
+	SubscriberTypeWebhook            SubscriberType = "webhook"
+	SubscriberTypeSlack              SubscriberType = "slack"
+	SubscriberTypeTeams              SubscriberType = "teams"
+	SubscriberTypeIntegrationPartner SubscriberType = "integration_partner"
+
+	SubscriberStateActive      SubscriberState = "active"
+	SubscriberStateUnconfirmed SubscriberState = "unconfirmed"
+	SubscriberStateQuarantined SubscriberState = "quarantined"
+	SubscriberStateAll         SubscriberState = "all"
+)
+
+func (t SubscriberType) String() string {
+
+	return string(t)
+}
+
+func (st SubscriberState) String() string {
+
+	return string(st)
+}
+
+func (o *SubscriberListOptions) values() url.Values {
+
+	if o == nil {
+		return url.Values{}
+	}
+	v := o.ListOptions.values()
+	if o.Query != "" {
+		v.Set("q", o.Query)
+	}
+	if o.Type != "" {
+		v.Set("type", o.Type.String())
+	}
+	if o.State != "" {
+		v.Set("state", o.State.String())
+	}
+	return v
+}
+
+// GetSubscribers returns every page subscriber matching opts, walking all
+// pages of the listing.
+func (s StatusPage) GetSubscribers(opts *SubscriberListOptions) ([]Subscriber, error) {
+	return s.GetSubscribersWithContext(context.Background(), opts)
+}
+
+func (s StatusPage) GetSubscribersWithContext(ctx context.Context, opts *SubscriberListOptions) ([]Subscriber, error) {
+
+	var subscribers []Subscriber
+	it := s.IterateSubscribers(opts)
+	for it.Next(ctx) {
+		subscribers = append(subscribers, it.Subscriber())
+	}
+
+	return subscribers, it.Err()
+
+}
+
+// ListSubscribers returns a single page of page subscribers matching opts.
+func (s StatusPage) ListSubscribers(opts *SubscriberListOptions) ([]Subscriber, error) {
+	return s.ListSubscribersWithContext(context.Background(), opts)
+}
+
+func (s StatusPage) ListSubscribersWithContext(ctx context.Context, opts *SubscriberListOptions) ([]Subscriber, error) {
+
+	var subscribers []Subscriber
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/subscribers"),
+		query:      opts.values(),
+		out:        &subscribers,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return subscribers, err
+
+}
+
+// SubscriberIterator walks the subscribers of a page lazily, fetching one
+// page of results at a time.
+type SubscriberIterator struct {
+	p   pager
+	buf []Subscriber
+	cur Subscriber
+}
+
+// IterateSubscribers returns an iterator over all subscribers matching opts,
+// starting at the page it selects.
+func (s StatusPage) IterateSubscribers(opts *SubscriberListOptions) *SubscriberIterator {
+
+	filter := SubscriberListOptions{}
+	if opts != nil {
+		filter = *opts
+	}
+	it := &SubscriberIterator{}
+	it.p = newPager(&filter.ListOptions, func(ctx context.Context, o ListOptions) (int, error) {
+		f := filter
+		f.ListOptions = o
+		page, err := s.ListSubscribersWithContext(ctx, &f)
+		it.buf = append(it.buf, page...)
+		return len(page), err
+	})
+	return it
+}
+
+// Next advances to the next subscriber, returning false when there are no
+// more subscribers or an error occurred.
+func (it *SubscriberIterator) Next(ctx context.Context) bool {
+
+	for len(it.buf) == 0 {
+		if !it.p.next(ctx) {
+			return false
+		}
+	}
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Subscriber returns the current subscriber.
+func (it *SubscriberIterator) Subscriber() Subscriber {
+
+	return it.cur
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SubscriberIterator) Err() error {
+
+	return it.p.err
+}
+
+func (s StatusPage) GetSubscriber(id string) (Subscriber, error) {
+	return s.GetSubscriberWithContext(context.Background(), id)
+}
+
+func (s StatusPage) GetSubscriberWithContext(ctx context.Context, id string) (Subscriber, error) {
+
+	var sub Subscriber
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/subscribers/%s", id),
+		out:        &sub,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return sub, err
+
+}
+
+// CreateSubscriber subscribes the email address, phone number or webhook
+// endpoint of sub to the page, or to the components in sub.ComponentIDs.
+func (s StatusPage) CreateSubscriber(sub Subscriber) (Subscriber, error) {
+	return s.CreateSubscriberWithContext(context.Background(), sub)
+}
+
+func (s StatusPage) CreateSubscriberWithContext(ctx context.Context, sub Subscriber) (Subscriber, error) {
+
+	s.Client.logger().Debug("creating subscriber", "mode", sub.Mode)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/subscribers"),
+		body:   ReqSubscriber{Subscriber: subscriberPayload(sub)},
+		out:    &sub,
+		expect: http.StatusCreated,
+	})
+	if err != nil {
+		return sub, err
+	}
+	s.Client.logger().Info("subscriber created", "subscriber_id", sub.ID)
+
+	return sub, nil
+
+}
+
+// UnsubscribeSubscriber removes sub from the page.
+func (s StatusPage) UnsubscribeSubscriber(sub Subscriber) error {
+	return s.UnsubscribeSubscriberWithContext(context.Background(), sub)
+}
+
+func (s StatusPage) UnsubscribeSubscriberWithContext(ctx context.Context, sub Subscriber) error {
+
+	s.Client.logger().Debug("unsubscribing subscriber", "subscriber_id", sub.ID)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/subscribers/%s", sub.ID),
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+	s.Client.logger().Info("subscriber unsubscribed", "subscriber_id", sub.ID)
+	return nil
+
+}
+
+// ResendSubscriberConfirmation sends the confirmation message to sub again.
+func (s StatusPage) ResendSubscriberConfirmation(sub Subscriber) error {
+	return s.ResendSubscriberConfirmationWithContext(context.Background(), sub)
+}
+
+func (s StatusPage) ResendSubscriberConfirmationWithContext(ctx context.Context, sub Subscriber) error {
+
+	s.Client.logger().Debug("resending subscriber confirmation", "subscriber_id", sub.ID)
+	return s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/subscribers/%s/resend_confirmation", sub.ID),
+	})
+
+}
+
+// ReactivateSubscribers reactivates the quarantined subscribers with the
+// given ids.
+func (s StatusPage) ReactivateSubscribers(ids []string) error {
+	return s.ReactivateSubscribersWithContext(context.Background(), ids)
+}
+
+func (s StatusPage) ReactivateSubscribersWithContext(ctx context.Context, ids []string) error {
+
+	s.Client.logger().Debug("reactivating subscribers", "count", len(ids))
+	return s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/subscribers/reactivate"),
+		body:   subscriberBulk{Subscribers: ids},
+	})
+
+}
+
+// UnsubscribeSubscribers removes the subscribers with the given ids from the
+// page in a single call. skipNotification suppresses the message telling
+// them they were unsubscribed.
+func (s StatusPage) UnsubscribeSubscribers(ids []string, skipNotification bool) error {
+	return s.UnsubscribeSubscribersWithContext(context.Background(), ids, skipNotification)
+}
+
+func (s StatusPage) UnsubscribeSubscribersWithContext(ctx context.Context, ids []string, skipNotification bool) error {
+
+	s.Client.logger().Debug("unsubscribing subscribers", "count", len(ids))
+	return s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/subscribers/unsubscribe"),
+		body:   subscriberBulk{Subscribers: ids, SkipUnsubscriptionNotification: skipNotification},
+	})
+
+}
+
+// ListIncidentSubscribers returns the subscribers of the incident with the
+// given id.
+func (s StatusPage) ListIncidentSubscribers(incidentID string) ([]Subscriber, error) {
+	return s.ListIncidentSubscribersWithContext(context.Background(), incidentID)
+}
+
+func (s StatusPage) ListIncidentSubscribersWithContext(ctx context.Context, incidentID string) ([]Subscriber, error) {
+
+	var subscribers []Subscriber
+	err := s.Client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       s.pagePath("/incidents/%s/subscribers", incidentID),
+		out:        &subscribers,
+		expect:     http.StatusOK,
+		idempotent: true,
+	})
+
+	return subscribers, err
+
+}
+
+// CreateIncidentSubscriber subscribes sub to updates of a single incident.
+func (s StatusPage) CreateIncidentSubscriber(incidentID string, sub Subscriber) (Subscriber, error) {
+	return s.CreateIncidentSubscriberWithContext(context.Background(), incidentID, sub)
+}
+
+func (s StatusPage) CreateIncidentSubscriberWithContext(ctx context.Context, incidentID string, sub Subscriber) (Subscriber, error) {
+
+	payload := subscriberPayload(sub)
+	payload.ComponentIDs = nil
+	payload.PageAccessUserID = ""
+
+	s.Client.logger().Debug("creating incident subscriber", "incident_id", incidentID, "mode", sub.Mode)
+	err := s.Client.do(ctx, request{
+		method: http.MethodPost,
+		path:   s.pagePath("/incidents/%s/subscribers", incidentID),
+		body:   ReqSubscriber{Subscriber: payload},
+		out:    &sub,
+		expect: http.StatusCreated,
+	})
+	if err != nil {
+		return sub, err
+	}
+	s.Client.logger().Info("incident subscriber created", "incident_id", incidentID, "subscriber_id", sub.ID)
+
+	return sub, nil
+
+}
+
+// RemoveIncidentSubscriber unsubscribes sub from the incident with the given
+// id.
+func (s StatusPage) RemoveIncidentSubscriber(incidentID string, sub Subscriber) error {
+	return s.RemoveIncidentSubscriberWithContext(context.Background(), incidentID, sub)
+}
+
+func (s StatusPage) RemoveIncidentSubscriberWithContext(ctx context.Context, incidentID string, sub Subscriber) error {
+
+	s.Client.logger().Debug("removing incident subscriber", "incident_id", incidentID, "subscriber_id", sub.ID)
+	err := s.Client.do(ctx, request{
+		method:     http.MethodDelete,
+		path:       s.pagePath("/incidents/%s/subscribers/%s", incidentID, sub.ID),
+		idempotent: true,
+	})
+	if err != nil {
+		return err
+	}
+	s.Client.logger().Info("incident subscriber removed", "incident_id", incidentID, "subscriber_id", sub.ID)
+	return nil
+
+}
+
+// subscriberPayload keeps the fields of sub accepted when creating a
+// subscriber.
+func subscriberPayload(sub Subscriber) Subscriber {
+
+	return Subscriber{
+		Email:                        sub.Email,
+		Endpoint:                     sub.Endpoint,
+		PhoneNumber:                  sub.PhoneNumber,
+		PhoneCountry:                 sub.PhoneCountry,
+		ComponentIDs:                 sub.ComponentIDs,
+		PageAccessUserID:             sub.PageAccessUserID,
+		SkipConfirmationNotification: sub.SkipConfirmationNotification,
+	}
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// subscriberColumns are the columns written by ExportSubscribersCSV. The
+// email, phone_number, phone_country and component_ids columns are the ones
+// read back by ImportSubscribersCSV, so an export can be imported again.
+var subscriberColumns = []string{
+	"id",
+	"mode",
+	"email",
+	"phone_number",
+	"phone_country",
+	"endpoint",
+	"component_ids",
+	"created_at",
+	"quarantined_at",
+}
+
+type (
+	// SubscriberImportOptions tunes ImportSubscribersCSV.
+	SubscriberImportOptions struct {
+		// DefaultPhoneCountry is used for rows with a phone number and no
+		// phone_country, e.g. "us".
+		DefaultPhoneCountry string
+		// SkipConfirmationNotification creates the subscribers without
+		// sending them a confirmation message.
+		SkipConfirmationNotification bool
+		// DryRun only validates the rows.
+		DryRun bool
+	}
+
+	// SubscriberImportResult reports the outcome of every row of an import.
+	SubscriberImportResult struct {
+		Created  []Subscriber
+		Failures []SubscriberImportFailure
+	}
+
+	// SubscriberImportFailure is a row that was rejected, either while
+	// validating it or by the API.
+	SubscriberImportFailure struct {
+		// Line is the number of the record in the CSV input, the header
+		// being record 1.
+		Line   int
+		Record []string
+		Err    error
+	}
+
+	subscriberRow struct {
+		line   int
+		record []string
+		sub    Subscriber
+	}
+)
+
+func (f SubscriberImportFailure) Error() string {
+
+	return fmt.Sprintf("line %d: %s", f.Line, f.Err)
+}
+
+// ImportSubscribersCSV creates a page subscriber for every row of r. The
+// first row is a header naming the columns: email or phone_number is
+// required, phone_country and component_ids (separated by ";" or spaces) are
+// optional and other columns are ignored.
+//
+// All rows are validated before the first subscriber is created. Creation
+// goes through the rate limit of the Client, and a row rejected by the API
+// is reported in the result without stopping the import. The returned error
+// is only set when the input cannot be read or ctx is done.
+func (s StatusPage) ImportSubscribersCSV(r io.Reader, opts SubscriberImportOptions) (SubscriberImportResult, error) {
+	return s.ImportSubscribersCSVWithContext(context.Background(), r, opts)
+}
+
+func (s StatusPage) ImportSubscribersCSVWithContext(ctx context.Context, r io.Reader, opts SubscriberImportOptions) (SubscriberImportResult, error) {
+
+	var result SubscriberImportResult
+
+	rows, failures, err := readSubscriberRows(r, opts)
+	if err != nil {
+		return result, err
+	}
+	result.Failures = failures
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		sub, err := s.CreateSubscriberWithContext(ctx, row.sub)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			result.Failures = append(result.Failures, SubscriberImportFailure{Line: row.line, Record: row.record, Err: err})
+			continue
+		}
+		result.Created = append(result.Created, sub)
+	}
+	s.Client.logger().Info("subscribers imported", "created", len(result.Created), "failed", len(result.Failures))
+
+	return result, nil
+
+}
+
+func readSubscriberRows(r io.Reader, opts SubscriberImportOptions) ([]subscriberRow, []SubscriberImportFailure, error) {
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read csv header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	_, hasEmail := cols["email"]
+	_, hasPhone := cols["phone_number"]
+	if !hasEmail && !hasPhone {
+		return nil, nil, errors.New("csv header must have an email or phone_number column")
+	}
+
+	var (
+		rows     []subscriberRow
+		failures []SubscriberImportFailure
+	)
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) {
+				failures = append(failures, SubscriberImportFailure{Line: line, Record: record, Err: err})
+				continue
+			}
+			return nil, nil, err
+		}
+
+		field := func(name string) string {
+			i, ok := cols[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		sub := Subscriber{
+			Email:                        field("email"),
+			PhoneNumber:                  field("phone_number"),
+			PhoneCountry:                 field("phone_country"),
+			ComponentIDs:                 strings.FieldsFunc(field("component_ids"), isIDSeparator),
+			SkipConfirmationNotification: opts.SkipConfirmationNotification,
+		}
+		if sub.PhoneNumber != "" && sub.PhoneCountry == "" {
+			sub.PhoneCountry = opts.DefaultPhoneCountry
+		}
+		if err := validateSubscriber(sub); err != nil {
+			failures = append(failures, SubscriberImportFailure{Line: line, Record: record, Err: err})
+			continue
+		}
+		rows = append(rows, subscriberRow{line: line, record: record, sub: sub})
+	}
+
+	return rows, failures, nil
+}
+
+func validateSubscriber(sub Subscriber) error {
+
+	switch {
+	case sub.Email == "" && sub.PhoneNumber == "":
+		return errors.New("email or phone_number is required")
+	case sub.Email != "" && sub.PhoneNumber != "":
+		return errors.New("only one of email and phone_number may be set")
+	case sub.Email != "":
+		addr, err := mail.ParseAddress(sub.Email)
+		if err != nil {
+			return fmt.Errorf("invalid email %q: %w", sub.Email, err)
+		}
+		// only a bare address is accepted by the API, not "Name <address>"
+		if addr.Address != strings.TrimSpace(sub.Email) {
+			return fmt.Errorf("invalid email %q: expected a bare address", sub.Email)
+		}
+	default:
+		if strings.Trim(sub.PhoneNumber, "0123456789 -().+") != "" {
+			return fmt.Errorf("invalid phone_number %q", sub.PhoneNumber)
+		}
+		if sub.PhoneCountry == "" {
+			return errors.New("phone_country is required for phone numbers")
+		}
+	}
+	return nil
+}
+
+func isIDSeparator(r rune) bool {
+
+	return r == ';' || r == ',' || r == ' ' || r == '\t'
+}
+
+// ExportSubscribersCSV writes every page subscriber matching opts to w as
+// CSV, with a header row. Subscribers in every state are exported unless
+// opts sets State.
+func (s StatusPage) ExportSubscribersCSV(w io.Writer, opts *SubscriberListOptions) error {
+	return s.ExportSubscribersCSVWithContext(context.Background(), w, opts)
+}
+
+func (s StatusPage) ExportSubscribersCSVWithContext(ctx context.Context, w io.Writer, opts *SubscriberListOptions) error {
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(subscriberColumns); err != nil {
+		return err
+	}
+
+	it := s.IterateSubscribers(exportOptions(opts))
+	for it.Next(ctx) {
+		sub := it.Subscriber()
+		ids := sub.ComponentIDs
+		if len(ids) == 0 {
+			ids = sub.Components
+		}
+		err := cw.Write([]string{
+			sub.ID,
+			sub.Mode,
+			sub.Email,
+			sub.PhoneNumber,
+			sub.PhoneCountry,
+			sub.Endpoint,
+			strings.Join(ids, ";"),
+			formatTime(sub.CreatedAt),
+			formatTime(sub.QuarantinedAt),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+
+}
+
+// ExportSubscribersJSONL writes every page subscriber matching opts to w as
+// JSON Lines, one subscriber object per line. Subscribers in every state are
+// exported unless opts sets State.
+func (s StatusPage) ExportSubscribersJSONL(w io.Writer, opts *SubscriberListOptions) error {
+	return s.ExportSubscribersJSONLWithContext(context.Background(), w, opts)
+}
+
+func (s StatusPage) ExportSubscribersJSONLWithContext(ctx context.Context, w io.Writer, opts *SubscriberListOptions) error {
+
+	enc := json.NewEncoder(w)
+	it := s.IterateSubscribers(exportOptions(opts))
+	for it.Next(ctx) {
+		if err := enc.Encode(it.Subscriber()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+
+}
+
+// exportOptions returns a copy of opts selecting subscribers in every state
+// when no State is set, as the API only lists active ones by default.
+func exportOptions(opts *SubscriberListOptions) *SubscriberListOptions {
+
+	o := SubscriberListOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.State == "" {
+		o.State = SubscriberStateAll
+	}
+	return &o
+}
+
+func formatTime(t *time.Time) string {
+
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
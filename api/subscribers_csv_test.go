@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadSubscriberRows(t *testing.T) {
+
+	type failure struct {
+		line int
+		err  string
+	}
+
+	tests := []struct {
+		name     string
+		opts     SubscriberImportOptions
+		csv      string
+		rows     map[int]Subscriber
+		failures []failure
+		err      string
+	}{
+		{
+			name: "email and phone rows",
+			opts: SubscriberImportOptions{DefaultPhoneCountry: "us"},
+			csv: "Email,Phone_Number,Phone_Country,component_ids,notes\n" +
+				"ana@example.com,,,c1;c2,first\n" +
+				",+1 555 0100,,,second\n" +
+				",555-0101,fr,c3 c4,third\n",
+			rows: map[int]Subscriber{
+				2: {Email: "ana@example.com", ComponentIDs: []string{"c1", "c2"}},
+				3: {PhoneNumber: "+1 555 0100", PhoneCountry: "us"},
+				4: {PhoneNumber: "555-0101", PhoneCountry: "fr", ComponentIDs: []string{"c3", "c4"}},
+			},
+		},
+		{
+			name: "validation failures keep their line",
+			csv: "email,phone_number,phone_country\n" +
+				"ok@example.com,,\n" +
+				"Bob <bob@example.com>,,\n" +
+				"not an email,,\n" +
+				",,\n" +
+				"both@example.com,5550100,us\n" +
+				",555 0100,\n" +
+				",call me,us\n" +
+				"  spaced@example.com  ,,\n",
+			rows: map[int]Subscriber{
+				2: {Email: "ok@example.com"},
+				9: {Email: "spaced@example.com"},
+			},
+			failures: []failure{
+				{3, "expected a bare address"},
+				{4, "invalid email"},
+				{5, "email or phone_number is required"},
+				{6, "only one of email and phone_number"},
+				{7, "phone_country is required"},
+				{8, "invalid phone_number"},
+			},
+		},
+		{
+			name: "short records",
+			csv:  "phone_number,phone_country,email\n,,ana@example.com\n5550100\n",
+			rows: map[int]Subscriber{
+				2: {Email: "ana@example.com"},
+			},
+			failures: []failure{
+				{3, "phone_country is required"},
+			},
+		},
+		{
+			name: "malformed record",
+			csv:  "email\nana@example.com\nbad\"quote@example.com\nbea@example.com\n",
+			rows: map[int]Subscriber{
+				2: {Email: "ana@example.com"},
+				4: {Email: "bea@example.com"},
+			},
+			failures: []failure{
+				{3, "bare \""},
+			},
+		},
+		{
+			name: "skip confirmation",
+			opts: SubscriberImportOptions{SkipConfirmationNotification: true},
+			csv:  "email\nana@example.com\n",
+			rows: map[int]Subscriber{
+				2: {Email: "ana@example.com", SkipConfirmationNotification: true},
+			},
+		},
+		{
+			name: "no contact column",
+			csv:  "name,component_ids\nAna,c1\n",
+			err:  "email or phone_number column",
+		},
+		{
+			name: "empty input",
+			csv:  "",
+			err:  "unable to read csv header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, failures, err := readSubscriberRows(strings.NewReader(tt.csv), tt.opts)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[int]Subscriber{}
+			for _, r := range rows {
+				if len(r.sub.ComponentIDs) == 0 {
+					r.sub.ComponentIDs = nil
+				}
+				got[r.line] = r.sub
+			}
+			if tt.rows == nil {
+				tt.rows = map[int]Subscriber{}
+			}
+			if !reflect.DeepEqual(got, tt.rows) {
+				t.Errorf("rows = %+v, want %+v", got, tt.rows)
+			}
+
+			if len(failures) != len(tt.failures) {
+				t.Fatalf("got %d failures %v, want %d", len(failures), failures, len(tt.failures))
+			}
+			for i, f := range failures {
+				want := tt.failures[i]
+				if f.Line != want.line || !strings.Contains(f.Err.Error(), want.err) {
+					t.Errorf("failure %d = line %d %q, want line %d containing %q", i, f.Line, f.Err, want.line, want.err)
+				}
+			}
+		})
+	}
+}
+
+func TestExportSubscribersState(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		opts  *SubscriberListOptions
+		state string
+	}{
+		{name: "nil options", opts: nil, state: "all"},
+		{name: "no state", opts: &SubscriberListOptions{Type: SubscriberTypeEmail}, state: "all"},
+		{name: "explicit state", opts: &SubscriberListOptions{State: SubscriberStateQuarantined}, state: "quarantined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var states []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				states = append(states, r.URL.Query().Get("state"))
+				w.Write([]byte(`[{"id":"s1","mode":"email","email":"ana@example.com","components":["c1","c2"]}]`))
+			}))
+			defer srv.Close()
+			s := newTestClient(srv).Page("p1")
+
+			var before SubscriberListOptions
+			if tt.opts != nil {
+				before = *tt.opts
+			}
+
+			var csvOut, jsonOut bytes.Buffer
+			if err := s.ExportSubscribersCSV(&csvOut, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.ExportSubscribersJSONL(&jsonOut, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if want := []string{tt.state, tt.state}; !reflect.DeepEqual(states, want) {
+				t.Errorf("state queried %q, want %q", states, want)
+			}
+			if tt.opts != nil && *tt.opts != before {
+				t.Errorf("options changed to %+v, want %+v", *tt.opts, before)
+			}
+			wantCSV := strings.Join(subscriberColumns, ",") + "\ns1,email,ana@example.com,,,,c1;c2,,\n"
+			if csvOut.String() != wantCSV {
+				t.Errorf("csv = %q, want %q", csvOut.String(), wantCSV)
+			}
+			if n := strings.Count(jsonOut.String(), "\n"); n != 1 {
+				t.Errorf("got %d json lines, want 1", n)
+			}
+		})
+	}
+}
+
+func TestExportedSubscribersImportBack(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"s1","mode":"email","email":"ana@example.com","component_ids":["c1"]},
+			{"id":"s2","mode":"sms","phone_number":"5550100","phone_country":"us"}]`))
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	if err := newTestClient(srv).Page("p1").ExportSubscribersCSV(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	rows, failures, err := readSubscriberRows(&out, SubscriberImportOptions{})
+	if err != nil || len(failures) > 0 {
+		t.Fatalf("import failed: %v %v", err, failures)
+	}
+	if len(rows) != 2 || rows[0].sub.Email != "ana@example.com" || rows[1].sub.PhoneCountry != "us" {
+		t.Errorf("imported rows %+v", rows)
+	}
+}